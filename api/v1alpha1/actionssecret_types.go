@@ -0,0 +1,155 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ActionsSecretVisibility returns the visibility options of organization secrets
+type ActionsSecretVisibility string
+
+const (
+	// AllVisibility makes the secret available to every repository in the organization
+	AllVisibility ActionsSecretVisibility = "all"
+
+	// PrivateVisibility makes the secret available to private repositories in the organization
+	PrivateVisibility ActionsSecretVisibility = "private"
+
+	// SelectedVisibility makes the secret available to the selected repositories only
+	SelectedVisibility ActionsSecretVisibility = "selected"
+)
+
+// ActionsSecretSpec defines the desired state of ActionsSecret
+type ActionsSecretSpec struct {
+	// +optional
+	// +kubebuilder:validation:MaxLength 253
+	// RepositoryRef points to a Repository in the same Namespace the secrets are uploaded to.
+	// Either RepositoryRef or Organization must be set.
+	RepositoryRef string `json:"repositoryRef,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:MaxLength 100
+	// Organization is the name of the Github organization the secrets are uploaded to.
+	// Either RepositoryRef or Organization must be set.
+	Organization string `json:"organization,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Enum=all;private;selected
+	// Visibility controls which repositories of the Organization can use the secrets, defaults to private
+	Visibility ActionsSecretVisibility `json:"visibility,omitempty"`
+
+	// +optional
+	// SelectedRepositoryRefs points to Repositories in the same Namespace that can use
+	// the secrets when Visibility is selected
+	SelectedRepositoryRefs []string `json:"selectedRepositoryRefs,omitempty"`
+
+	// SecretRef points to the Secret in the same Namespace holding the secret values
+	SecretRef ActionsSecretSource `json:"secretRef"`
+}
+
+// ActionsSecretSource selects the data of a Secret to upload
+type ActionsSecretSource struct {
+	// +kubebuilder:validation:MaxLength 253
+	// Name of the Secret in the same Namespace
+	Name string `json:"name"`
+
+	// +optional
+	// Keys limits the uploaded data to these keys of the Secret, every key is uploaded when empty.
+	// Each key is uploaded as an Actions secret of the same name, upper-cased with `.` and `-`
+	// replaced by `_`.
+	Keys []string `json:"keys,omitempty"`
+}
+
+// ActionsSecretStatus defines the observed state of ActionsSecret
+type ActionsSecretStatus struct {
+	// +optional
+	// Status stores the status of the ActionsSecret
+	Status StatusReason `json:"status,omitempty"`
+
+	// +optional
+	// Message explains why the ActionsSecret is Invalid or Denied
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	// ContentHash is the hash of the uploaded values and their target.
+	// It is used to re-upload the secrets when the source Secret changes.
+	ContentHash string `json:"contentHash,omitempty"`
+
+	// +optional
+	// Secrets stores the names of the uploaded Actions secrets.
+	// It is used to remove secrets that are no longer part of the source.
+	Secrets []string `json:"secrets,omitempty"`
+
+	// +optional
+	// RetainedSecrets stores the secrets left on previous targets because --actual-delete is off.
+	// They are removed once the manager runs with --actual-delete.
+	RetainedSecrets []RetainedActionsSecret `json:"retainedSecrets,omitempty"`
+
+	// +optional
+	// GitHubRepository stores the repository the secrets were uploaded to, empty for organization secrets.
+	// It is used to ensure proper deletion in absence of a valid `ActionsSecretSpec.RepositoryRef`.
+	GitHubRepository string `json:"gitHubRepository,omitempty"`
+
+	// +optional
+	// GitHubOrganization stores the organization the secrets were uploaded to.
+	// It is used to ensure proper deletion in absence of a valid `ActionsSecretSpec.RepositoryRef`.
+	GitHubOrganization string `json:"gitHubOrganization,omitempty"`
+
+	// +optional
+	// PlannedChanges lists the changes the last reconcile skipped because the manager runs with --dry-run
+	PlannedChanges []string `json:"plannedChanges,omitempty"`
+}
+
+// RetainedActionsSecret is an Actions secret kept on a previous target
+type RetainedActionsSecret struct {
+	// Organization the secret was uploaded to
+	Organization string `json:"organization"`
+
+	// +optional
+	// Repository the secret was uploaded to, empty for organization secrets
+	Repository string `json:"repository,omitempty"`
+
+	// Name of the secret
+	Name string `json:"name"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=.status.status,description="Status of the ActionsSecret",name=Status,priority=0,type=string
+
+// ActionsSecret is the Schema for the actionssecrets API
+type ActionsSecret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ActionsSecretSpec   `json:"spec,omitempty"`
+	Status ActionsSecretStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ActionsSecretList contains a list of ActionsSecret
+type ActionsSecretList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ActionsSecret `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ActionsSecret{}, &ActionsSecretList{})
+}
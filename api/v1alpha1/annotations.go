@@ -0,0 +1,41 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PausedAnnotation stops the controllers from making any change on GitHub
+	// for the object while it is set to "true"
+	PausedAnnotation = "github.go.hein.dev/paused"
+
+	// ReconcileRequestedAtAnnotation requests a fresh reconcile with GitHub
+	// whenever its value changes, the handled value is recorded in the status
+	ReconcileRequestedAtAnnotation = "github.go.hein.dev/reconcile-requested-at"
+)
+
+// IsPaused reports whether the object is paused with the PausedAnnotation
+func IsPaused(obj metav1.Object) bool {
+	return obj.GetAnnotations()[PausedAnnotation] == "true"
+}
+
+// ReconcileRequestedAt returns the value of the ReconcileRequestedAtAnnotation
+func ReconcileRequestedAt(obj metav1.Object) string {
+	return obj.GetAnnotations()[ReconcileRequestedAtAnnotation]
+}
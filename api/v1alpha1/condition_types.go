@@ -0,0 +1,85 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionType returns the condition types
+type ConditionType string
+
+const (
+	// ImportedCondition reports whether the import of a repository finished
+	ImportedCondition ConditionType = "Imported"
+
+	// PausedCondition reports whether reconciling the object is paused with the PausedAnnotation
+	PausedCondition ConditionType = "Paused"
+
+	// AllowedCondition reports whether the OrganizationPolicies allow managing the repository
+	AllowedCondition ConditionType = "Allowed"
+)
+
+// Condition defines an observation of the state of an object
+type Condition struct {
+	// Type is the type of the condition
+	Type ConditionType `json:"type"`
+
+	// Status is the status of the condition, one of True, False or Unknown
+	Status corev1.ConditionStatus `json:"status"`
+
+	// +optional
+	// LastTransitionTime is the last time the status changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// +optional
+	// Reason is a machine readable explanation of the status
+	Reason string `json:"reason,omitempty"`
+
+	// +optional
+	// Message is a human readable explanation of the status
+	Message string `json:"message,omitempty"`
+}
+
+// FindCondition returns the condition of the type, nil when it is not set
+func FindCondition(conditions []Condition, conditionType ConditionType) *Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds or updates the condition of the same type, the
+// transition time is only updated when the status changes
+func SetCondition(conditions *[]Condition, condition Condition) {
+	current := FindCondition(*conditions, condition.Type)
+	if current == nil {
+		condition.LastTransitionTime = metav1.Now()
+		*conditions = append(*conditions, condition)
+		return
+	}
+
+	if current.Status != condition.Status {
+		current.LastTransitionTime = metav1.Now()
+	}
+	current.Status = condition.Status
+	current.Reason = condition.Reason
+	current.Message = condition.Message
+}
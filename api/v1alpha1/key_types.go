@@ -33,6 +33,20 @@ type KeySpec struct {
 	// +optional
 	// SecretTemplate sets annotations and labels on the resulting Secret of this Key
 	SecretTemplate KeySecretTemplate `json:"secretTemplate,omitempty"`
+
+	// +optional
+	// Export optionally uploads the private key as an encrypted Actions secret of another Repository
+	Export *KeyExport `json:"export,omitempty"`
+}
+
+// KeyExport points to the Actions secret the private key of a Key is exported to
+type KeyExport struct {
+	// +kubebuilder:validation:MaxLength 253
+	// RepositoryRef points to a Repository in the same Namespace whose Actions can use the private key
+	RepositoryRef string `json:"repositoryRef"`
+
+	// SecretName is the name of the Actions secret holding the private key
+	SecretName string `json:"secretName"`
 }
 
 // KeySecretTemplate is a template for creating Secrets that hold Key data
@@ -92,6 +106,38 @@ type KeyStatus struct {
 	// PublicKey holds the key contents matching the SSH private key.
 	// It is used by the Key controller to track correctness of the child Secret object.
 	PublicKey string `json:"publicKey"`
+
+	// +optional
+	// Export stores where the private key was exported to.
+	// It is used to re-export the private key when it is rotated and to ensure proper deletion.
+	Export *KeyExportStatus `json:"export,omitempty"`
+
+	// +optional
+	// PlannedChanges lists the changes the last reconcile skipped because the manager runs with --dry-run
+	PlannedChanges []string `json:"plannedChanges,omitempty"`
+
+	// +optional
+	// LastHandledReconcileAt is the value of the reconcile-requested-at annotation of the last completed reconcile
+	LastHandledReconcileAt string `json:"lastHandledReconcileAt,omitempty"`
+
+	// +optional
+	// Conditions are the latest observations of the state of the key
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// KeyExportStatus defines the observed state of an exported private key
+type KeyExportStatus struct {
+	// GitHubRepository stores the repository holding the Actions secret
+	GitHubRepository string `json:"gitHubRepository"`
+
+	// GitHubOrganization stores the organization expected to contain the repository
+	GitHubOrganization string `json:"gitHubOrganization"`
+
+	// SecretName stores the name of the Actions secret
+	SecretName string `json:"secretName"`
+
+	// PublicKey holds the public key matching the exported private key
+	PublicKey string `json:"publicKey"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,131 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CustomPropertyValueType returns the types of values of a custom property
+type CustomPropertyValueType string
+
+const (
+	// StringCustomProperty allows any string value
+	StringCustomProperty CustomPropertyValueType = "string"
+
+	// SingleSelectCustomProperty allows one of the allowed values
+	SingleSelectCustomProperty CustomPropertyValueType = "single_select"
+
+	// MultiSelectCustomProperty allows several of the allowed values
+	MultiSelectCustomProperty CustomPropertyValueType = "multi_select"
+
+	// TrueFalseCustomProperty allows true or false
+	TrueFalseCustomProperty CustomPropertyValueType = "true_false"
+)
+
+// OrganizationCustomPropertySpec defines the desired state of OrganizationCustomProperty
+type OrganizationCustomPropertySpec struct {
+	// +kubebuilder:validation:MaxLength 100
+	// Organization is the name of the Github organization the property is defined in
+	Organization string `json:"organization"`
+
+	// +optional
+	// +kubebuilder:validation:MaxLength=75
+	// PropertyName is the name of the custom property, metadata.name is used when empty
+	PropertyName string `json:"propertyName,omitempty"`
+
+	// +kubebuilder:validation:Enum=string;single_select;multi_select;true_false
+	// ValueType is the type of the values of the property
+	ValueType CustomPropertyValueType `json:"valueType"`
+
+	// +optional
+	// Required means every repository has to have a value, DefaultValue is used when none is set
+	Required bool `json:"required,omitempty"`
+
+	// +optional
+	// DefaultValue is the value of repositories without a value when the property is required
+	DefaultValue string `json:"defaultValue,omitempty"`
+
+	// +optional
+	// Description is a short description of the property
+	Description string `json:"description,omitempty"`
+
+	// +optional
+	// AllowedValues are the values of single_select and multi_select properties
+	AllowedValues []string `json:"allowedValues,omitempty"`
+}
+
+// OrganizationCustomPropertyStatus defines the observed state of OrganizationCustomProperty
+type OrganizationCustomPropertyStatus struct {
+	// +optional
+	// Status stores the status of the OrganizationCustomProperty
+	Status StatusReason `json:"status,omitempty"`
+
+	// +optional
+	// Message explains why the OrganizationCustomProperty is Denied
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	// GitHubOrganization stores the organization the property was defined in.
+	// It is used to ensure proper deletion when the spec changes.
+	GitHubOrganization string `json:"gitHubOrganization,omitempty"`
+
+	// +optional
+	// PropertyName stores the name the property was defined with.
+	// It is used to ensure proper deletion when the spec changes.
+	PropertyName string `json:"propertyName,omitempty"`
+
+	// +optional
+	// PlannedChanges lists the changes the last reconcile skipped because the manager runs with --dry-run
+	PlannedChanges []string `json:"plannedChanges,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=.spec.organization,description="Organization of the property",name=Organization,priority=0,type=string
+// +kubebuilder:printcolumn:JSONPath=.status.status,description="Status of the OrganizationCustomProperty",name=Status,priority=0,type=string
+
+// OrganizationCustomProperty is the Schema for the organizationcustomproperties API
+type OrganizationCustomProperty struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OrganizationCustomPropertySpec   `json:"spec,omitempty"`
+	Status OrganizationCustomPropertyStatus `json:"status,omitempty"`
+}
+
+// GitHubPropertyName returns the name of the custom property on GitHub
+func (in *OrganizationCustomProperty) GitHubPropertyName() string {
+	if in.Spec.PropertyName != "" {
+		return in.Spec.PropertyName
+	}
+	return in.Name
+}
+
+// +kubebuilder:object:root=true
+
+// OrganizationCustomPropertyList contains a list of OrganizationCustomProperty
+type OrganizationCustomPropertyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OrganizationCustomProperty `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OrganizationCustomProperty{}, &OrganizationCustomPropertyList{})
+}
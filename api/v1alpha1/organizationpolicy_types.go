@@ -0,0 +1,85 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RepositoryVisibility returns the visibilities of repositories
+// +kubebuilder:validation:Enum=public;private
+type RepositoryVisibility string
+
+const (
+	// PublicRepository is a repository everyone can see
+	PublicRepository RepositoryVisibility = "public"
+
+	// PrivateRepository is a repository only members with access can see
+	PrivateRepository RepositoryVisibility = "private"
+)
+
+// OrganizationPolicySpec defines the desired state of OrganizationPolicy
+type OrganizationPolicySpec struct {
+	// +kubebuilder:validation:MaxLength=100
+	// Organization is the name of the Github organization the policy applies to
+	Organization string `json:"organization"`
+
+	// +kubebuilder:validation:MinItems=1
+	// Namespaces are the namespaces allowed to manage repositories in the organization
+	Namespaces []string `json:"namespaces"`
+
+	// +optional
+	// NamePrefix is the prefix every repository name in the organization has to start with
+	NamePrefix string `json:"namePrefix,omitempty"`
+
+	// +optional
+	// Visibilities are the allowed visibilities of the repositories, any visibility is allowed when empty
+	Visibilities []RepositoryVisibility `json:"visibilities,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// MaxRepositories is the number of repositories each namespace may manage in the organization
+	MaxRepositories *int32 `json:"maxRepositories,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:JSONPath=.spec.organization,description="Organization the policy applies to",name=Organization,priority=0,type=string
+// +kubebuilder:printcolumn:JSONPath=.spec.namespaces,description="Namespaces allowed to manage repositories",name=Namespaces,priority=0,type=string
+
+// OrganizationPolicy is the Schema for the organizationpolicies API, once any
+// OrganizationPolicy exists repositories can only be managed in organizations
+// with a policy allowing them
+type OrganizationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OrganizationPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OrganizationPolicyList contains a list of OrganizationPolicy
+type OrganizationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OrganizationPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OrganizationPolicy{}, &OrganizationPolicyList{})
+}
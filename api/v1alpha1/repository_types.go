@@ -23,9 +23,17 @@ import (
 // RepositorySpec defines the desired state of Repository
 type RepositorySpec struct {
 	// +kubebuilder:validation:MaxLength 100
-	// Organization is the name of the Github organization
+	// Organization is the name of the Github organization.
+	// Changing it transfers the repository to the new organization.
 	Organization string `json:"organization"`
 
+	// +optional
+	// +kubebuilder:validation:MaxLength=100
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.-]+$`
+	// Name is the name of the Github repository, metadata.name is used when empty.
+	// Changing it renames the repository.
+	Name string `json:"name,omitempty"`
+
 	// +kubebuilder:validation:MaxLength 80
 	// +optional
 	// Description is the description of the repository
@@ -38,6 +46,70 @@ type RepositorySpec struct {
 	// +optional
 	// Settings contains all the settings repository settings
 	Settings RepositorySettings `json:"settings,omitempty"`
+
+	// +optional
+	// DefaultBranch is the default branch of the repository, the current default branch is kept when empty
+	DefaultBranch string `json:"defaultBranch,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Enum=Rename;Switch
+	// DefaultBranchStrategy controls how DefaultBranch is applied, Rename (default) renames the current
+	// default branch while Switch makes an existing branch the default
+	DefaultBranchStrategy DefaultBranchStrategy `json:"defaultBranchStrategy,omitempty"`
+
+	// +optional
+	// FromTemplate generates the repository from a template repository when it is created
+	FromTemplate *RepositoryTemplate `json:"fromTemplate,omitempty"`
+
+	// +optional
+	// ForkFrom creates the repository as a fork of another repository, it is ignored when FromTemplate is set
+	ForkFrom *RepositoryFork `json:"forkFrom,omitempty"`
+
+	// +optional
+	// Import copies the branches and tags of a repository on another git host into the repository
+	// once it is created, Init is ignored when Import is set
+	Import *RepositoryImport `json:"import,omitempty"`
+
+	// +optional
+	// Init contains the options used to initialize the repository when it is created,
+	// it is ignored when FromTemplate, ForkFrom or Import is set
+	Init RepositoryInit `json:"init,omitempty"`
+
+	// +optional
+	// Variables are the GitHub Actions variables of the repository
+	Variables []RepositoryVariable `json:"variables,omitempty"`
+
+	// +optional
+	// Environments are the deployment environments of the repository
+	Environments []RepositoryEnvironment `json:"environments,omitempty"`
+
+	// +optional
+	// Security contains the security and analysis features of the repository
+	Security *RepositorySecurity `json:"security,omitempty"`
+
+	// +optional
+	// Labels are the issue labels of the repository
+	Labels []RepositoryLabel `json:"labels,omitempty"`
+
+	// +optional
+	// PruneUnmanagedLabels removes the labels that are not in Labels, including the GitHub
+	// defaults, once Labels is set. By default only labels removed from Labels are deleted.
+	// Labels are only deleted with --actual-delete.
+	PruneUnmanagedLabels bool `json:"pruneUnmanagedLabels,omitempty"`
+
+	// +optional
+	// Pages configures the GitHub Pages site of the repository, removing it unpublishes the site
+	Pages *RepositoryPages `json:"pages,omitempty"`
+
+	// +optional
+	// Autolinks are the autolink references of the repository, once set every other
+	// autolink reference is removed
+	Autolinks []RepositoryAutolink `json:"autolinks,omitempty"`
+
+	// +optional
+	// CustomProperties are the values of organization custom properties of the repository,
+	// they are set when the repository is created
+	CustomProperties []RepositoryCustomProperty `json:"customProperties,omitempty"`
 }
 
 // RepositorySettings defines the desired settings
@@ -63,6 +135,318 @@ type RepositorySettings struct {
 	Template bool `json:"template,omitempty"`
 }
 
+// DefaultBranchStrategy returns the options to apply the default branch
+type DefaultBranchStrategy string
+
+const (
+	// RenameDefaultBranch renames the current default branch
+	RenameDefaultBranch DefaultBranchStrategy = "Rename"
+
+	// SwitchDefaultBranch makes an existing branch the default branch
+	SwitchDefaultBranch DefaultBranchStrategy = "Switch"
+)
+
+// RepositoryTemplate defines the template repository a repository is generated from
+type RepositoryTemplate struct {
+	// Owner is the organization or user owning the template repository
+	Owner string `json:"owner"`
+
+	// Repository is the name of the template repository
+	Repository string `json:"repository"`
+
+	// +optional
+	// IncludeAllBranches copies every branch of the template instead of only the default branch
+	IncludeAllBranches bool `json:"includeAllBranches,omitempty"`
+}
+
+// RepositoryFork defines the repository a repository is forked from
+type RepositoryFork struct {
+	// Owner is the organization or user owning the upstream repository
+	Owner string `json:"owner"`
+
+	// Repository is the name of the upstream repository
+	Repository string `json:"repository"`
+
+	// +optional
+	// DefaultBranchOnly copies only the default branch of the upstream repository
+	DefaultBranchOnly bool `json:"defaultBranchOnly,omitempty"`
+}
+
+// RepositoryImport defines the git repository a repository is imported from
+type RepositoryImport struct {
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^(https|http|file)://`
+	// URL is the https git URL of the source repository, e.g. https://gitlab.com/org/repo.git.
+	// http:// and file:// URLs are only imported when the manager allows insecure import sources.
+	URL string `json:"url"`
+
+	// +optional
+	// CredentialsSecretRef points to a Secret in the same Namespace with the username and
+	// password keys used to clone the source repository over HTTP(S)
+	CredentialsSecretRef string `json:"credentialsSecretRef,omitempty"`
+}
+
+// RepositoryForkStatus defines the observed relation of a fork to its parent
+type RepositoryForkStatus struct {
+	// Parent is the repository the fork was created from as owner/name
+	Parent string `json:"parent"`
+
+	// +optional
+	// AheadBy is the amount of commits on the default branch that are not on the parent default branch
+	AheadBy int `json:"aheadBy,omitempty"`
+
+	// +optional
+	// BehindBy is the amount of commits on the parent default branch that are not on the default branch
+	BehindBy int `json:"behindBy,omitempty"`
+}
+
+// RepositoryInit defines how a repository is initialized when it is created
+type RepositoryInit struct {
+	// +optional
+	// AutoInit creates an initial commit with an empty README
+	AutoInit bool `json:"autoInit,omitempty"`
+
+	// +optional
+	// GitignoreTemplate is the name of the .gitignore template to commit, e.g. Go
+	GitignoreTemplate string `json:"gitignoreTemplate,omitempty"`
+
+	// +optional
+	// LicenseTemplate is the keyword of the license to commit, e.g. apache-2.0
+	LicenseTemplate string `json:"licenseTemplate,omitempty"`
+
+	// +optional
+	// DefaultBranch is the name of the initial branch, the organization default is used when empty.
+	// It requires an initial commit from AutoInit, GitignoreTemplate or LicenseTemplate and is
+	// applied by renaming the initial branch until the repository is synced for the first time.
+	DefaultBranch string `json:"defaultBranch,omitempty"`
+}
+
+// RepositoryInitStatus records how the controller initialized a repository it created
+type RepositoryInitStatus struct {
+	// +optional
+	// AutoInit is true when the repository was created with an initial commit with an empty README
+	AutoInit bool `json:"autoInit,omitempty"`
+
+	// +optional
+	// GitignoreTemplate is the .gitignore template the repository was created with
+	GitignoreTemplate string `json:"gitignoreTemplate,omitempty"`
+
+	// +optional
+	// LicenseTemplate is the license the repository was created with
+	LicenseTemplate string `json:"licenseTemplate,omitempty"`
+
+	// +optional
+	// DefaultBranch is the initial branch requested when the repository was created
+	DefaultBranch string `json:"defaultBranch,omitempty"`
+}
+
+// RepositorySecurity defines the security and analysis features of a repository,
+// features that are not set are left untouched
+type RepositorySecurity struct {
+	// +optional
+	// DependabotAlerts enables alerts for vulnerable dependencies
+	DependabotAlerts *bool `json:"dependabotAlerts,omitempty"`
+
+	// +optional
+	// DependabotSecurityUpdates enables pull requests updating vulnerable dependencies,
+	// it requires DependabotAlerts
+	DependabotSecurityUpdates *bool `json:"dependabotSecurityUpdates,omitempty"`
+
+	// +optional
+	// SecretScanning enables scanning the repository for leaked secrets
+	SecretScanning *bool `json:"secretScanning,omitempty"`
+
+	// +optional
+	// SecretScanningPushProtection blocks pushes containing secrets, it requires SecretScanning
+	SecretScanningPushProtection *bool `json:"secretScanningPushProtection,omitempty"`
+
+	// +optional
+	// PrivateVulnerabilityReporting allows security researchers to report vulnerabilities privately
+	PrivateVulnerabilityReporting *bool `json:"privateVulnerabilityReporting,omitempty"`
+}
+
+// RepositorySecurityStatus defines the observed security and analysis features of a repository
+type RepositorySecurityStatus struct {
+	// DependabotAlerts is whether alerts for vulnerable dependencies are enabled
+	DependabotAlerts bool `json:"dependabotAlerts"`
+
+	// DependabotSecurityUpdates is whether security update pull requests are enabled
+	DependabotSecurityUpdates bool `json:"dependabotSecurityUpdates"`
+
+	// SecretScanning is whether secret scanning is enabled
+	SecretScanning bool `json:"secretScanning"`
+
+	// SecretScanningPushProtection is whether push protection is enabled
+	SecretScanningPushProtection bool `json:"secretScanningPushProtection"`
+
+	// PrivateVulnerabilityReporting is whether private vulnerability reporting is enabled
+	PrivateVulnerabilityReporting bool `json:"privateVulnerabilityReporting"`
+}
+
+// PagesBuildType returns the options to build a GitHub Pages site
+type PagesBuildType string
+
+const (
+	// LegacyPagesBuild builds the site from a branch
+	LegacyPagesBuild PagesBuildType = "legacy"
+
+	// WorkflowPagesBuild builds the site with a GitHub Actions workflow
+	WorkflowPagesBuild PagesBuildType = "workflow"
+)
+
+// RepositoryPages defines the GitHub Pages site of a repository
+type RepositoryPages struct {
+	// +optional
+	// +kubebuilder:validation:Enum=legacy;workflow
+	// BuildType is how the site is built, legacy (default) builds it from Source
+	// while workflow leaves it to a GitHub Actions workflow
+	BuildType PagesBuildType `json:"buildType,omitempty"`
+
+	// +optional
+	// Source is the branch and path the site is built from when BuildType is legacy,
+	// the root of the default branch is used when empty
+	Source *PagesSource `json:"source,omitempty"`
+
+	// +optional
+	// CNAME is the custom domain of the site
+	CNAME string `json:"cname,omitempty"`
+
+	// +optional
+	// HTTPSEnforced redirects HTTP requests to HTTPS, it requires the certificate of the custom domain to be issued.
+	// It is left to GitHub when unset, which always enforces HTTPS for *.github.io sites.
+	HTTPSEnforced *bool `json:"httpsEnforced,omitempty"`
+}
+
+// PagesSource defines where a GitHub Pages site is built from
+type PagesSource struct {
+	// Branch is the branch the site is built from
+	Branch string `json:"branch"`
+
+	// +optional
+	// +kubebuilder:validation:Enum=/;/docs
+	// Path is the directory the site is built from, defaults to /
+	Path string `json:"path,omitempty"`
+}
+
+// RepositoryPagesStatus defines the observed GitHub Pages site of a repository
+type RepositoryPagesStatus struct {
+	// +optional
+	// URL is the address the site is published at
+	URL string `json:"url,omitempty"`
+
+	// +optional
+	// BuildStatus is the state of the latest build, e.g. built, building or errored
+	BuildStatus string `json:"buildStatus,omitempty"`
+}
+
+// RepositoryAutolink defines an autolink reference turning a key prefix into a link
+type RepositoryAutolink struct {
+	// +kubebuilder:validation:MinLength=1
+	// KeyPrefix is the prefix that is turned into a link, e.g. JIRA-
+	KeyPrefix string `json:"keyPrefix"`
+
+	// +kubebuilder:validation:Pattern=`<num>`
+	// URLTemplate is the URL linked to, <num> is replaced with the reference, e.g. https://jira.org.com/browse/JIRA-<num>
+	URLTemplate string `json:"urlTemplate"`
+
+	// +optional
+	// Alphanumeric matches alphanumeric references instead of numeric ones only, defaults to true
+	Alphanumeric *bool `json:"alphanumeric,omitempty"`
+}
+
+// RepositoryCustomProperty defines the value of an organization custom property
+type RepositoryCustomProperty struct {
+	// Name is the name of the custom property
+	Name string `json:"name"`
+
+	// +optional
+	// Value is the value of string, single_select and true_false properties
+	Value string `json:"value,omitempty"`
+
+	// +optional
+	// Values are the values of multi_select properties
+	Values []string `json:"values,omitempty"`
+}
+
+// RepositoryLabel defines an issue label
+type RepositoryLabel struct {
+	// +kubebuilder:validation:MaxLength=50
+	// Name is the name of the label, it is matched case insensitively
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Pattern=`^[0-9a-fA-F]{6}$`
+	// Color is the hexadecimal color code of the label without the leading #
+	Color string `json:"color"`
+
+	// +optional
+	// +kubebuilder:validation:MaxLength=100
+	// Description is a short description of the label
+	Description string `json:"description,omitempty"`
+}
+
+// RepositoryVariable defines a GitHub Actions variable
+type RepositoryVariable struct {
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_]*$`
+	// Name is the name of the variable
+	Name string `json:"name"`
+
+	// Value is the value of the variable
+	Value string `json:"value"`
+}
+
+// RepositoryEnvironment defines a deployment environment and its protection rules
+type RepositoryEnvironment struct {
+	// Name is the name of the environment
+	Name string `json:"name"`
+
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=43200
+	// WaitTimer is the amount of minutes to wait before allowing deployments to proceed
+	WaitTimer int `json:"waitTimer,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:MaxItems=6
+	// Reviewers are the users or teams that have to approve deployments
+	Reviewers []EnvironmentReviewer `json:"reviewers,omitempty"`
+
+	// +optional
+	// DeploymentBranchPolicy restricts which branches can deploy, every branch can deploy when unset
+	DeploymentBranchPolicy *DeploymentBranchPolicy `json:"deploymentBranchPolicy,omitempty"`
+}
+
+// ReviewerType returns the reviewer options
+type ReviewerType string
+
+const (
+	// UserReviewer means the reviewer is a user
+	UserReviewer ReviewerType = "User"
+
+	// TeamReviewer means the reviewer is a team of the organization
+	TeamReviewer ReviewerType = "Team"
+)
+
+// EnvironmentReviewer defines a user or team that has to approve deployments
+type EnvironmentReviewer struct {
+	// +kubebuilder:validation:Enum=User;Team
+	// Type is the type of the reviewer
+	Type ReviewerType `json:"type"`
+
+	// Name is the login of the user or the slug of the team
+	Name string `json:"name"`
+}
+
+// DeploymentBranchPolicy defines which branches can deploy to an environment
+type DeploymentBranchPolicy struct {
+	// +optional
+	// ProtectedBranches means only branches with branch protection rules can deploy
+	ProtectedBranches bool `json:"protectedBranches,omitempty"`
+
+	// +optional
+	// Branches are the name patterns of branches that can deploy, when ProtectedBranches is false
+	Branches []string `json:"branches,omitempty"`
+}
+
 // StatusReason returns the Status options
 type StatusReason string
 
@@ -81,6 +465,12 @@ const (
 
 	// DeletingStatus means the repository is in deleting status
 	DeletingStatus StatusReason = "Deleting"
+
+	// InvalidStatus means the object can't be synced until its spec or source is fixed
+	InvalidStatus StatusReason = "Invalid"
+
+	// DeniedStatus means the organization policies don't allow the object to change GitHub
+	DeniedStatus StatusReason = "Denied"
 )
 
 // RepositoryStatus defines the observed state of Repository
@@ -104,6 +494,84 @@ type RepositoryStatus struct {
 	// +optional
 	// WatchersCount is amount of watchers when it was last synced
 	WatchersCount int `json:"watchersCount,omitempty"`
+
+	// +optional
+	// DefaultBranch is the default branch when it was last synced
+	DefaultBranch string `json:"defaultBranch,omitempty"`
+
+	// +optional
+	// GitHubID stores the GitHub API ID of the repository.
+	// It is used to find the repository after it was renamed or transferred.
+	GitHubID int64 `json:"gitHubID,omitempty"`
+
+	// +optional
+	// GitHubNodeID stores the GitHub GraphQL node ID of the repository
+	GitHubNodeID string `json:"gitHubNodeID,omitempty"`
+
+	// +optional
+	// GitHubName stores the name of the repository when it was last synced
+	GitHubName string `json:"gitHubName,omitempty"`
+
+	// +optional
+	// GitHubOrganization stores the owner of the repository when it was last synced
+	GitHubOrganization string `json:"gitHubOrganization,omitempty"`
+
+	// +optional
+	// Conditions are the latest observations of the state of the repository
+	Conditions []Condition `json:"conditions,omitempty"`
+
+	// +optional
+	// PlannedChanges lists the changes the last reconcile skipped because the manager runs with --dry-run
+	PlannedChanges []string `json:"plannedChanges,omitempty"`
+
+	// +optional
+	// LastHandledReconcileAt is the value of the reconcile-requested-at annotation of the last completed reconcile
+	LastHandledReconcileAt string `json:"lastHandledReconcileAt,omitempty"`
+
+	// +optional
+	// Fork stores the parent of the repository and how far it diverged when it was last synced,
+	// it is only reported for forks
+	Fork *RepositoryForkStatus `json:"fork,omitempty"`
+
+	// +optional
+	// Init stores the spec.init options the repository was created with,
+	// it is only reported for repositories the controller created and initialized
+	Init *RepositoryInitStatus `json:"init,omitempty"`
+
+	// +optional
+	// ManagedVariables stores the names of the variables managed by the controller.
+	// It is used to remove variables that are removed from the spec.
+	ManagedVariables []string `json:"managedVariables,omitempty"`
+
+	// +optional
+	// ManagedEnvironments stores the names of the environments managed by the controller.
+	// It is used to remove environments that are removed from the spec.
+	ManagedEnvironments []string `json:"managedEnvironments,omitempty"`
+
+	// +optional
+	// ManagedLabels stores the names of the labels managed by the controller.
+	// It is used to remove labels that are removed from the spec.
+	ManagedLabels []string `json:"managedLabels,omitempty"`
+
+	// +optional
+	// ManagedAutolinks stores the key prefixes of the autolink references managed by the controller.
+	// It is used to remove autolink references when they are all removed from the spec.
+	ManagedAutolinks []string `json:"managedAutolinks,omitempty"`
+
+	// +optional
+	// ManagedCustomProperties stores the names of the custom properties managed by the controller.
+	// It is used to unset the values of properties that are removed from the spec.
+	ManagedCustomProperties []string `json:"managedCustomProperties,omitempty"`
+
+	// +optional
+	// Pages stores the GitHub Pages site when it was last synced.
+	// It is used to unpublish the site when it is removed from the spec.
+	Pages *RepositoryPagesStatus `json:"pages,omitempty"`
+
+	// +optional
+	// Security stores the security and analysis features when it was last synced,
+	// it is only reported when spec.security is set
+	Security *RepositorySecurityStatus `json:"security,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -119,6 +587,14 @@ type Repository struct {
 	Status RepositoryStatus `json:"status,omitempty"`
 }
 
+// GitHubName returns the name of the GitHub repository
+func (in *Repository) GitHubName() string {
+	if in.Spec.Name != "" {
+		return in.Spec.Name
+	}
+	return in.Name
+}
+
 // +kubebuilder:object:root=true
 
 // RepositoryList contains a list of Repository
@@ -0,0 +1,149 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RepositoryFileSpec defines the desired state of RepositoryFile
+type RepositoryFileSpec struct {
+	// +kubebuilder:validation:MaxLength 253
+	// RepositoryRef points to a Repository in the same Namespace the file is committed to
+	RepositoryRef string `json:"repositoryRef"`
+
+	// +kubebuilder:validation:MinLength=1
+	// Path is the path of the file in the repository, e.g. .github/CODEOWNERS
+	Path string `json:"path"`
+
+	// +optional
+	// Branch is the branch the file is committed to, the default branch is used when empty
+	Branch string `json:"branch,omitempty"`
+
+	// +optional
+	// Content is the content of the file.
+	// Either Content or ContentFrom must be set.
+	Content string `json:"content,omitempty"`
+
+	// +optional
+	// ContentFrom reads the content of the file from a ConfigMap.
+	// Either Content or ContentFrom must be set.
+	ContentFrom *RepositoryFileSource `json:"contentFrom,omitempty"`
+
+	// +optional
+	// CommitMessage is the message of the commits writing the file, a message naming the path is used when empty
+	CommitMessage string `json:"commitMessage,omitempty"`
+
+	// +optional
+	// Author is the author and committer of the commits, the authenticated user is used when empty
+	Author *CommitAuthor `json:"author,omitempty"`
+
+	// +optional
+	// PullRequestOnProtectedBranch opens a pull request instead of committing directly
+	// when the branch is protected
+	PullRequestOnProtectedBranch bool `json:"pullRequestOnProtectedBranch,omitempty"`
+}
+
+// RepositoryFileSource selects the ConfigMap key holding the file content
+type RepositoryFileSource struct {
+	// ConfigMapKeyRef points to a key of a ConfigMap in the same Namespace
+	ConfigMapKeyRef ConfigMapKeySelector `json:"configMapKeyRef"`
+}
+
+// ConfigMapKeySelector selects a key of a ConfigMap
+type ConfigMapKeySelector struct {
+	// +kubebuilder:validation:MaxLength 253
+	// Name of the ConfigMap in the same Namespace
+	Name string `json:"name"`
+
+	// Key of the ConfigMap holding the content
+	Key string `json:"key"`
+}
+
+// CommitAuthor defines the author of a commit
+type CommitAuthor struct {
+	// Name is the name of the author
+	Name string `json:"name"`
+
+	// Email is the email address of the author
+	Email string `json:"email"`
+}
+
+// RepositoryFileStatus defines the observed state of RepositoryFile
+type RepositoryFileStatus struct {
+	// +optional
+	// Status stores the status of the RepositoryFile
+	Status StatusReason `json:"status,omitempty"`
+
+	// +optional
+	// Message explains why the RepositoryFile is Denied
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	// SHA is the git blob SHA of the content when it was last synced.
+	// It is compared with the blob SHA on GitHub to detect drift.
+	SHA string `json:"sha,omitempty"`
+
+	// +optional
+	// PullRequestURL is the URL of the pull request proposing the content on a protected branch
+	PullRequestURL string `json:"pullRequestURL,omitempty"`
+
+	// +optional
+	// GitHubRepository stores the repository the file was committed to.
+	// It is used to ensure proper deletion in absence of a valid `RepositoryFileSpec.RepositoryRef`.
+	GitHubRepository string `json:"gitHubRepository,omitempty"`
+
+	// +optional
+	// GitHubOrganization stores the organization of the repository the file was committed to.
+	// It is used to ensure proper deletion in absence of a valid `RepositoryFileSpec.RepositoryRef`.
+	GitHubOrganization string `json:"gitHubOrganization,omitempty"`
+
+	// +optional
+	// Branch stores the branch the file was committed to
+	Branch string `json:"branch,omitempty"`
+
+	// +optional
+	// PlannedChanges lists the changes the last reconcile skipped because the manager runs with --dry-run
+	PlannedChanges []string `json:"plannedChanges,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=.spec.path,description="Path of the file",name=Path,priority=0,type=string
+// +kubebuilder:printcolumn:JSONPath=.status.status,description="Status of the RepositoryFile",name=Status,priority=0,type=string
+
+// RepositoryFile is the Schema for the repositoryfiles API
+type RepositoryFile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RepositoryFileSpec   `json:"spec,omitempty"`
+	Status RepositoryFileStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RepositoryFileList contains a list of RepositoryFile
+type RepositoryFileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RepositoryFile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RepositoryFile{}, &RepositoryFileList{})
+}
@@ -25,13 +25,224 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionsSecret) DeepCopyInto(out *ActionsSecret) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionsSecret.
+func (in *ActionsSecret) DeepCopy() *ActionsSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionsSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActionsSecret) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionsSecretList) DeepCopyInto(out *ActionsSecretList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ActionsSecret, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionsSecretList.
+func (in *ActionsSecretList) DeepCopy() *ActionsSecretList {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionsSecretList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActionsSecretList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionsSecretSource) DeepCopyInto(out *ActionsSecretSource) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionsSecretSource.
+func (in *ActionsSecretSource) DeepCopy() *ActionsSecretSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionsSecretSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionsSecretSpec) DeepCopyInto(out *ActionsSecretSpec) {
+	*out = *in
+	if in.SelectedRepositoryRefs != nil {
+		in, out := &in.SelectedRepositoryRefs, &out.SelectedRepositoryRefs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.SecretRef.DeepCopyInto(&out.SecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionsSecretSpec.
+func (in *ActionsSecretSpec) DeepCopy() *ActionsSecretSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionsSecretSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionsSecretStatus) DeepCopyInto(out *ActionsSecretStatus) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetainedSecrets != nil {
+		in, out := &in.RetainedSecrets, &out.RetainedSecrets
+		*out = make([]RetainedActionsSecret, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionsSecretStatus.
+func (in *ActionsSecretStatus) DeepCopy() *ActionsSecretStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionsSecretStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommitAuthor) DeepCopyInto(out *CommitAuthor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitAuthor.
+func (in *CommitAuthor) DeepCopy() *CommitAuthor {
+	if in == nil {
+		return nil
+	}
+	out := new(CommitAuthor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeySelector) DeepCopyInto(out *ConfigMapKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeySelector.
+func (in *ConfigMapKeySelector) DeepCopy() *ConfigMapKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentBranchPolicy) DeepCopyInto(out *DeploymentBranchPolicy) {
+	*out = *in
+	if in.Branches != nil {
+		in, out := &in.Branches, &out.Branches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentBranchPolicy.
+func (in *DeploymentBranchPolicy) DeepCopy() *DeploymentBranchPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentBranchPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentReviewer) DeepCopyInto(out *EnvironmentReviewer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentReviewer.
+func (in *EnvironmentReviewer) DeepCopy() *EnvironmentReviewer {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentReviewer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Key) DeepCopyInto(out *Key) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Key.
@@ -52,6 +263,36 @@ func (in *Key) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyExport) DeepCopyInto(out *KeyExport) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyExport.
+func (in *KeyExport) DeepCopy() *KeyExport {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyExportStatus) DeepCopyInto(out *KeyExportStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyExportStatus.
+func (in *KeyExportStatus) DeepCopy() *KeyExportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyExportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeyList) DeepCopyInto(out *KeyList) {
 	*out = *in
@@ -117,6 +358,11 @@ func (in *KeySecretTemplate) DeepCopy() *KeySecretTemplate {
 func (in *KeySpec) DeepCopyInto(out *KeySpec) {
 	*out = *in
 	in.SecretTemplate.DeepCopyInto(&out.SecretTemplate)
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(KeyExport)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeySpec.
@@ -132,6 +378,23 @@ func (in *KeySpec) DeepCopy() *KeySpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeyStatus) DeepCopyInto(out *KeyStatus) {
 	*out = *in
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(KeyExportStatus)
+		**out = **in
+	}
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyStatus.
@@ -145,26 +408,26 @@ func (in *KeyStatus) DeepCopy() *KeyStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Repository) DeepCopyInto(out *Repository) {
+func (in *OrganizationCustomProperty) DeepCopyInto(out *OrganizationCustomProperty) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Repository.
-func (in *Repository) DeepCopy() *Repository {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationCustomProperty.
+func (in *OrganizationCustomProperty) DeepCopy() *OrganizationCustomProperty {
 	if in == nil {
 		return nil
 	}
-	out := new(Repository)
+	out := new(OrganizationCustomProperty)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Repository) DeepCopyObject() runtime.Object {
+func (in *OrganizationCustomProperty) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -172,31 +435,31 @@ func (in *Repository) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepositoryList) DeepCopyInto(out *RepositoryList) {
+func (in *OrganizationCustomPropertyList) DeepCopyInto(out *OrganizationCustomPropertyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Repository, len(*in))
+		*out = make([]OrganizationCustomProperty, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryList.
-func (in *RepositoryList) DeepCopy() *RepositoryList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationCustomPropertyList.
+func (in *OrganizationCustomPropertyList) DeepCopy() *OrganizationCustomPropertyList {
 	if in == nil {
 		return nil
 	}
-	out := new(RepositoryList)
+	out := new(OrganizationCustomPropertyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RepositoryList) DeepCopyObject() runtime.Object {
+func (in *OrganizationCustomPropertyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -204,47 +467,778 @@ func (in *RepositoryList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepositorySettings) DeepCopyInto(out *RepositorySettings) {
+func (in *OrganizationCustomPropertySpec) DeepCopyInto(out *OrganizationCustomPropertySpec) {
 	*out = *in
+	if in.AllowedValues != nil {
+		in, out := &in.AllowedValues, &out.AllowedValues
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositorySettings.
-func (in *RepositorySettings) DeepCopy() *RepositorySettings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationCustomPropertySpec.
+func (in *OrganizationCustomPropertySpec) DeepCopy() *OrganizationCustomPropertySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RepositorySettings)
+	out := new(OrganizationCustomPropertySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepositorySpec) DeepCopyInto(out *RepositorySpec) {
+func (in *OrganizationCustomPropertyStatus) DeepCopyInto(out *OrganizationCustomPropertyStatus) {
 	*out = *in
-	out.Settings = in.Settings
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositorySpec.
-func (in *RepositorySpec) DeepCopy() *RepositorySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationCustomPropertyStatus.
+func (in *OrganizationCustomPropertyStatus) DeepCopy() *OrganizationCustomPropertyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RepositorySpec)
+	out := new(OrganizationCustomPropertyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepositoryStatus) DeepCopyInto(out *RepositoryStatus) {
+func (in *OrganizationPolicy) DeepCopyInto(out *OrganizationPolicy) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryStatus.
-func (in *RepositoryStatus) DeepCopy() *RepositoryStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationPolicy.
+func (in *OrganizationPolicy) DeepCopy() *OrganizationPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(RepositoryStatus)
+	out := new(OrganizationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OrganizationPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrganizationPolicyList) DeepCopyInto(out *OrganizationPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OrganizationPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationPolicyList.
+func (in *OrganizationPolicyList) DeepCopy() *OrganizationPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(OrganizationPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OrganizationPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrganizationPolicySpec) DeepCopyInto(out *OrganizationPolicySpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Visibilities != nil {
+		in, out := &in.Visibilities, &out.Visibilities
+		*out = make([]RepositoryVisibility, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxRepositories != nil {
+		in, out := &in.MaxRepositories, &out.MaxRepositories
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationPolicySpec.
+func (in *OrganizationPolicySpec) DeepCopy() *OrganizationPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OrganizationPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagesSource) DeepCopyInto(out *PagesSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagesSource.
+func (in *PagesSource) DeepCopy() *PagesSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PagesSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Repository) DeepCopyInto(out *Repository) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Repository.
+func (in *Repository) DeepCopy() *Repository {
+	if in == nil {
+		return nil
+	}
+	out := new(Repository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Repository) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryAutolink) DeepCopyInto(out *RepositoryAutolink) {
+	*out = *in
+	if in.Alphanumeric != nil {
+		in, out := &in.Alphanumeric, &out.Alphanumeric
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryAutolink.
+func (in *RepositoryAutolink) DeepCopy() *RepositoryAutolink {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryAutolink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryCustomProperty) DeepCopyInto(out *RepositoryCustomProperty) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryCustomProperty.
+func (in *RepositoryCustomProperty) DeepCopy() *RepositoryCustomProperty {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryCustomProperty)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryEnvironment) DeepCopyInto(out *RepositoryEnvironment) {
+	*out = *in
+	if in.Reviewers != nil {
+		in, out := &in.Reviewers, &out.Reviewers
+		*out = make([]EnvironmentReviewer, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeploymentBranchPolicy != nil {
+		in, out := &in.DeploymentBranchPolicy, &out.DeploymentBranchPolicy
+		*out = new(DeploymentBranchPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryEnvironment.
+func (in *RepositoryEnvironment) DeepCopy() *RepositoryEnvironment {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryEnvironment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryFile) DeepCopyInto(out *RepositoryFile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryFile.
+func (in *RepositoryFile) DeepCopy() *RepositoryFile {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepositoryFile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryFileList) DeepCopyInto(out *RepositoryFileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RepositoryFile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryFileList.
+func (in *RepositoryFileList) DeepCopy() *RepositoryFileList {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryFileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepositoryFileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryFileSource) DeepCopyInto(out *RepositoryFileSource) {
+	*out = *in
+	out.ConfigMapKeyRef = in.ConfigMapKeyRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryFileSource.
+func (in *RepositoryFileSource) DeepCopy() *RepositoryFileSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryFileSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryFileSpec) DeepCopyInto(out *RepositoryFileSpec) {
+	*out = *in
+	if in.ContentFrom != nil {
+		in, out := &in.ContentFrom, &out.ContentFrom
+		*out = new(RepositoryFileSource)
+		**out = **in
+	}
+	if in.Author != nil {
+		in, out := &in.Author, &out.Author
+		*out = new(CommitAuthor)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryFileSpec.
+func (in *RepositoryFileSpec) DeepCopy() *RepositoryFileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryFileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryFileStatus) DeepCopyInto(out *RepositoryFileStatus) {
+	*out = *in
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryFileStatus.
+func (in *RepositoryFileStatus) DeepCopy() *RepositoryFileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryFileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryFork) DeepCopyInto(out *RepositoryFork) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryFork.
+func (in *RepositoryFork) DeepCopy() *RepositoryFork {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryFork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryForkStatus) DeepCopyInto(out *RepositoryForkStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryForkStatus.
+func (in *RepositoryForkStatus) DeepCopy() *RepositoryForkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryForkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryImport) DeepCopyInto(out *RepositoryImport) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryImport.
+func (in *RepositoryImport) DeepCopy() *RepositoryImport {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryImport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryInit) DeepCopyInto(out *RepositoryInit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryInit.
+func (in *RepositoryInit) DeepCopy() *RepositoryInit {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryInit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryInitStatus) DeepCopyInto(out *RepositoryInitStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryInitStatus.
+func (in *RepositoryInitStatus) DeepCopy() *RepositoryInitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryInitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryLabel) DeepCopyInto(out *RepositoryLabel) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryLabel.
+func (in *RepositoryLabel) DeepCopy() *RepositoryLabel {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryLabel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryList) DeepCopyInto(out *RepositoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Repository, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryList.
+func (in *RepositoryList) DeepCopy() *RepositoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepositoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryPages) DeepCopyInto(out *RepositoryPages) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(PagesSource)
+		**out = **in
+	}
+	if in.HTTPSEnforced != nil {
+		in, out := &in.HTTPSEnforced, &out.HTTPSEnforced
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryPages.
+func (in *RepositoryPages) DeepCopy() *RepositoryPages {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryPages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryPagesStatus) DeepCopyInto(out *RepositoryPagesStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryPagesStatus.
+func (in *RepositoryPagesStatus) DeepCopy() *RepositoryPagesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryPagesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositorySecurity) DeepCopyInto(out *RepositorySecurity) {
+	*out = *in
+	if in.DependabotAlerts != nil {
+		in, out := &in.DependabotAlerts, &out.DependabotAlerts
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DependabotSecurityUpdates != nil {
+		in, out := &in.DependabotSecurityUpdates, &out.DependabotSecurityUpdates
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SecretScanning != nil {
+		in, out := &in.SecretScanning, &out.SecretScanning
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SecretScanningPushProtection != nil {
+		in, out := &in.SecretScanningPushProtection, &out.SecretScanningPushProtection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PrivateVulnerabilityReporting != nil {
+		in, out := &in.PrivateVulnerabilityReporting, &out.PrivateVulnerabilityReporting
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositorySecurity.
+func (in *RepositorySecurity) DeepCopy() *RepositorySecurity {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositorySecurity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositorySecurityStatus) DeepCopyInto(out *RepositorySecurityStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositorySecurityStatus.
+func (in *RepositorySecurityStatus) DeepCopy() *RepositorySecurityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositorySecurityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositorySettings) DeepCopyInto(out *RepositorySettings) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositorySettings.
+func (in *RepositorySettings) DeepCopy() *RepositorySettings {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositorySettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositorySpec) DeepCopyInto(out *RepositorySpec) {
+	*out = *in
+	out.Settings = in.Settings
+	if in.FromTemplate != nil {
+		in, out := &in.FromTemplate, &out.FromTemplate
+		*out = new(RepositoryTemplate)
+		**out = **in
+	}
+	if in.ForkFrom != nil {
+		in, out := &in.ForkFrom, &out.ForkFrom
+		*out = new(RepositoryFork)
+		**out = **in
+	}
+	if in.Import != nil {
+		in, out := &in.Import, &out.Import
+		*out = new(RepositoryImport)
+		**out = **in
+	}
+	out.Init = in.Init
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make([]RepositoryVariable, len(*in))
+		copy(*out, *in)
+	}
+	if in.Environments != nil {
+		in, out := &in.Environments, &out.Environments
+		*out = make([]RepositoryEnvironment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(RepositorySecurity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]RepositoryLabel, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pages != nil {
+		in, out := &in.Pages, &out.Pages
+		*out = new(RepositoryPages)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autolinks != nil {
+		in, out := &in.Autolinks, &out.Autolinks
+		*out = make([]RepositoryAutolink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CustomProperties != nil {
+		in, out := &in.CustomProperties, &out.CustomProperties
+		*out = make([]RepositoryCustomProperty, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositorySpec.
+func (in *RepositorySpec) DeepCopy() *RepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryStatus) DeepCopyInto(out *RepositoryStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Fork != nil {
+		in, out := &in.Fork, &out.Fork
+		*out = new(RepositoryForkStatus)
+		**out = **in
+	}
+	if in.Init != nil {
+		in, out := &in.Init, &out.Init
+		*out = new(RepositoryInitStatus)
+		**out = **in
+	}
+	if in.ManagedVariables != nil {
+		in, out := &in.ManagedVariables, &out.ManagedVariables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedEnvironments != nil {
+		in, out := &in.ManagedEnvironments, &out.ManagedEnvironments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedLabels != nil {
+		in, out := &in.ManagedLabels, &out.ManagedLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedAutolinks != nil {
+		in, out := &in.ManagedAutolinks, &out.ManagedAutolinks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedCustomProperties != nil {
+		in, out := &in.ManagedCustomProperties, &out.ManagedCustomProperties
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pages != nil {
+		in, out := &in.Pages, &out.Pages
+		*out = new(RepositoryPagesStatus)
+		**out = **in
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(RepositorySecurityStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryStatus.
+func (in *RepositoryStatus) DeepCopy() *RepositoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryTemplate) DeepCopyInto(out *RepositoryTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryTemplate.
+func (in *RepositoryTemplate) DeepCopy() *RepositoryTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryVariable) DeepCopyInto(out *RepositoryVariable) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryVariable.
+func (in *RepositoryVariable) DeepCopy() *RepositoryVariable {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryVariable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetainedActionsSecret) DeepCopyInto(out *RetainedActionsSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetainedActionsSecret.
+func (in *RetainedActionsSecret) DeepCopy() *RetainedActionsSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(RetainedActionsSecret)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,312 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1alpha1 "go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+)
+
+var (
+	actionsSecretFinalizerName = "actionssecret.finalizers.github.go.hein.dev"
+)
+
+// ActionsSecretReconciler reconciles a ActionsSecret object
+type ActionsSecretReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	GitClient    git.Client
+	ActualDelete bool
+
+	// Recorder records an event for every change made on GitHub
+	Recorder record.EventRecorder
+
+	// Selector optionally limits the reconciled ActionsSecrets to the ones with matching labels
+	Selector labels.Selector
+}
+
+// +kubebuilder:rbac:groups=github.go.hein.dev,resources=actionssecrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=github.go.hein.dev,resources=actionssecrets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=github.go.hein.dev,resources=repositories,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=github.go.hein.dev,resources=organizationpolicies,verbs=get;list;watch
+
+// Reconcile is responsible for reconciling the request
+func (r *ActionsSecretReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	var requeueAfter = 2 * time.Second
+	ctx, plan := git.WithPlan(context.Background())
+	log := r.Log.WithValues("actionssecret", req.NamespacedName)
+
+	var actionsSecret v1alpha1.ActionsSecret
+	if err := r.Client.Get(ctx, req.NamespacedName, &actionsSecret); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !selected(r.Selector, &actionsSecret) {
+		return ctrl.Result{}, nil
+	}
+
+	// the changes skipped by the dry-run client are stored whichever way the reconcile ends
+	defer func() {
+		if err := r.updateActionsSecretPlan(ctx, &actionsSecret, plan); err != nil {
+			log.Error(err, "unable to update planned changes")
+		}
+	}()
+
+	// handle finalizers before any other reconcile logic can fail
+	if !actionsSecret.ObjectMeta.DeletionTimestamp.IsZero() &&
+		containsString(actionsSecret.GetFinalizers(), actionsSecretFinalizerName) {
+		log.Info("handle deletion", "name", actionsSecret.Name)
+		if err := r.handleDeletion(ctx, &actionsSecret); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// fetch the Secret holding the values
+	secretRef := types.NamespacedName{Name: actionsSecret.Spec.SecretRef.Name, Namespace: req.Namespace}
+	log = log.WithValues("secret", secretRef)
+	var secret corev1.Secret
+	if err := r.Client.Get(ctx, secretRef, &secret); err != nil {
+		if errors.IsNotFound(err) {
+			r.updateActionsSecretStatus(ctx, &actionsSecret, v1alpha1.WaitingStatus)
+			log.Info("referenced secret does not exist")
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		}
+		log.Error(err, "unexpected error fetching referenced secret")
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
+
+	// resolve where the secrets are uploaded to
+	org, repoName := actionsSecret.Spec.Organization, ""
+	var repository *v1alpha1.Repository
+	if actionsSecret.Spec.RepositoryRef != "" {
+		var ready bool
+		var err error
+		repository, ready, err = syncedRepository(ctx, r.Client, req.Namespace, actionsSecret.Spec.RepositoryRef)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: requeueAfter}, err
+		}
+		if !ready {
+			r.updateActionsSecretStatus(ctx, &actionsSecret, v1alpha1.WaitingStatus)
+			log.Info("referenced repository not yet synced", "repository", actionsSecret.Spec.RepositoryRef)
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		}
+		org, repoName = repository.Spec.Organization, repository.GitHubName()
+	}
+	if org == "" {
+		return ctrl.Result{}, fmt.Errorf("ActionsSecret %q must set either repositoryRef or organization", actionsSecret.Name)
+	}
+
+	// targets the organization policies don't allow are left alone on GitHub
+	allowed, policyErr := r.checkPolicy(ctx, &actionsSecret, org, repository)
+	if policyErr != nil {
+		return ctrl.Result{}, policyErr
+	}
+	if !allowed {
+		log.Info("actions secret not allowed by organization policies", "organization", org, "repository", repoName)
+		return ctrl.Result{}, nil
+	}
+
+	visibility := actionsSecret.Spec.Visibility
+	if repoName != "" {
+		visibility = ""
+	} else if visibility == "" {
+		visibility = v1alpha1.PrivateVisibility
+	}
+
+	var selectedRepositoryIDs []int64
+	if visibility == v1alpha1.SelectedVisibility {
+		for _, ref := range actionsSecret.Spec.SelectedRepositoryRefs {
+			repository, ready, err := syncedRepository(ctx, r.Client, req.Namespace, ref)
+			if err != nil {
+				return ctrl.Result{RequeueAfter: requeueAfter}, err
+			}
+			if !ready {
+				r.updateActionsSecretStatus(ctx, &actionsSecret, v1alpha1.WaitingStatus)
+				log.Info("selected repository not yet synced", "repository", ref)
+				return ctrl.Result{RequeueAfter: requeueAfter}, nil
+			}
+
+			ghRepo, _, err := r.GitClient.GetRepo(ctx, repository.Spec.Organization, repository.GitHubName())
+			if err != nil {
+				return ctrl.Result{RequeueAfter: requeueAfter}, err
+			}
+			selectedRepositoryIDs = append(selectedRepositoryIDs, ghRepo.GetID())
+		}
+	}
+
+	// names GitHub would reject are not retried until the Secret or spec changes
+	values, err := actionsSecretValues(&actionsSecret, &secret)
+	if err != nil {
+		log.Info("invalid actions secret", "reason", err.Error())
+		return ctrl.Result{}, r.updateActionsSecretMessage(ctx, &actionsSecret, v1alpha1.InvalidStatus, err.Error())
+	}
+
+	// secrets kept while --actual-delete was off are removed once it is on
+	stale := staleActionsSecrets(&actionsSecret, org, repoName, values)
+	contentHash := actionsSecretHash(org, repoName, string(visibility), selectedRepositoryIDs, values)
+	if actionsSecret.Status.Status == v1alpha1.SyncedStatus && actionsSecret.Status.ContentHash == contentHash &&
+		(!r.ActualDelete || len(stale) == 0) {
+		return ctrl.Result{}, nil
+	}
+
+	// Secret and target are both ready, add finalizer for github-Delete before uploading
+	if actionsSecret.ObjectMeta.DeletionTimestamp.IsZero() &&
+		!containsString(actionsSecret.GetFinalizers(), actionsSecretFinalizerName) {
+		log.Info("adding finalizer", "name", actionsSecret.Name)
+		if err := r.addFinalizer(ctx, &actionsSecret); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	r.updateActionsSecretStatus(ctx, &actionsSecret, v1alpha1.UpdatingStatus)
+
+	names := sortedKeys(values)
+	for _, name := range names {
+		log.Info("uploading actions secret", "organization", org, "repository", repoName, "name", name)
+		if repoName != "" {
+			err = r.GitClient.CreateOrUpdateRepoSecret(ctx, org, repoName, name, values[name])
+		} else {
+			err = r.GitClient.CreateOrUpdateOrgSecret(ctx, org, name, string(visibility), selectedRepositoryIDs, values[name])
+		}
+		if err != nil {
+			return ctrl.Result{RequeueAfter: requeueAfter}, err
+		}
+	}
+	r.Recorder.Eventf(&actionsSecret, corev1.EventTypeNormal, "Updated", "uploaded %d actions secrets to %s", len(names), actionsSecretTarget(org, repoName))
+
+	// remove secrets that are no longer part of the source or were left on a previous
+	// target, the ones that are kept stay in the status until they are removed
+	managed := append([]string{}, names...)
+	var retained []v1alpha1.RetainedActionsSecret
+	for _, leftover := range stale {
+		previous := actionsSecretTarget(leftover.Organization, leftover.Repository)
+		if !r.ActualDelete {
+			log.Info("actual delete false, leaving actions secret", "organization", leftover.Organization, "repository", leftover.Repository, "name", leftover.Name)
+			r.Recorder.Eventf(&actionsSecret, corev1.EventTypeNormal, "DeleteSkipped", "kept actions secret %s in %s, --actual-delete is off", leftover.Name, previous)
+			if leftover.Organization == org && leftover.Repository == repoName {
+				managed = append(managed, leftover.Name)
+			} else {
+				retained = append(retained, leftover)
+			}
+			continue
+		}
+		log.Info("removing actions secret", "organization", leftover.Organization, "repository", leftover.Repository, "name", leftover.Name)
+		if err := r.deleteGitHubSecret(ctx, leftover.Organization, leftover.Repository, leftover.Name); err != nil {
+			return ctrl.Result{RequeueAfter: requeueAfter}, err
+		}
+		r.Recorder.Eventf(&actionsSecret, corev1.EventTypeNormal, "Deleted", "deleted actions secret %s from %s", leftover.Name, previous)
+	}
+	sort.Strings(managed)
+
+	// the dry-run client only planned the changes, GitHub still holds the previous
+	// values so they must not be recorded as synced
+	if changes := plan.Changes(); len(changes) > 0 {
+		log.Info("planned actions secret changes", "count", len(changes))
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.updateActionsSecretStatusDetails(ctx, &actionsSecret, org, repoName, contentHash, managed, retained); err != nil {
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
+
+	log.Info("uploaded actions secrets", "count", len(names))
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager configures the controller
+func (r *ActionsSecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("actionssecret-controller")
+	}
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ActionsSecret{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.secretToActionsSecrets),
+		}).
+		Watches(&source.Kind{Type: &v1alpha1.OrganizationPolicy{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.policyToActionsSecrets),
+		})
+
+	if r.Selector != nil {
+		builder = builder.WithEventFilter(selectorPredicate(r.Selector, &v1alpha1.ActionsSecret{}))
+	}
+
+	return builder.Complete(r)
+}
+
+// secretToActionsSecrets queues every ActionsSecret sourcing its values from the Secret
+func (r *ActionsSecretReconciler) secretToActionsSecrets(obj handler.MapObject) []reconcile.Request {
+	var list v1alpha1.ActionsSecretList
+	if err := r.Client.List(context.Background(), &list, client.InNamespace(obj.Meta.GetNamespace())); err != nil {
+		r.Log.Error(err, "unable to list actions secrets", "namespace", obj.Meta.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, actionsSecret := range list.Items {
+		if actionsSecret.Spec.SecretRef.Name == obj.Meta.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: actionsSecret.Namespace, Name: actionsSecret.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// policyToActionsSecrets queues every ActionsSecret, a policy can allow or deny
+// the targets of any of them
+func (r *ActionsSecretReconciler) policyToActionsSecrets(obj handler.MapObject) []reconcile.Request {
+	var list v1alpha1.ActionsSecretList
+	if err := r.Client.List(context.Background(), &list); err != nil {
+		r.Log.Error(err, "unable to list actions secrets", "organizationPolicy", obj.Meta.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, actionsSecret := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: actionsSecret.Namespace, Name: actionsSecret.Name},
+		})
+	}
+	return requests
+}
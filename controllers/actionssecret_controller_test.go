@@ -0,0 +1,147 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+)
+
+var _ = Describe("Run ActionsSecret Controller", func() {
+	const timeout = time.Second * 10
+	const interval = time.Millisecond * 500
+
+	Context("Run an ActionsSecret without its Secret", func() {
+		It("Should wait without a finalizer", func() {
+			key := types.NamespacedName{Name: "test-waiting-actions-secret", Namespace: "default"}
+			actionsSecret := &v1alpha1.ActionsSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: v1alpha1.ActionsSecretSpec{
+					Organization: "awsctrl",
+					SecretRef:    v1alpha1.ActionsSecretSource{Name: "missing-secret"},
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), actionsSecret)).Should(Succeed())
+
+			By("Describing Waiting Status")
+			Eventually(func() bool {
+				a := &v1alpha1.ActionsSecret{}
+				k8sClient.Get(context.Background(), key, a)
+				return a.Status.Status == v1alpha1.WaitingStatus && len(a.GetFinalizers()) == 0
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(k8sClient.Delete(context.Background(), actionsSecret)).Should(Succeed())
+			Eventually(func() bool {
+				return isGone(key, &v1alpha1.ActionsSecret{})
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+
+	Context("Run an ActionsSecret with a name GitHub rejects", func() {
+		It("Should be Invalid", func() {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-invalid-actions-source", Namespace: "default"},
+				Data:       map[string][]byte{"GITHUB_TOKEN": []byte("value")},
+			}
+			Expect(k8sClient.Create(context.Background(), secret)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), secret)).Should(Succeed())
+			}()
+
+			key := types.NamespacedName{Name: "test-invalid-actions-secret", Namespace: "default"}
+			actionsSecret := &v1alpha1.ActionsSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: v1alpha1.ActionsSecretSpec{
+					Organization: "awsctrl",
+					SecretRef:    v1alpha1.ActionsSecretSource{Name: secret.Name},
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), actionsSecret)).Should(Succeed())
+
+			By("Describing Invalid Status")
+			Eventually(func() bool {
+				a := &v1alpha1.ActionsSecret{}
+				k8sClient.Get(context.Background(), key, a)
+				return a.Status.Status == v1alpha1.InvalidStatus && a.Status.Message != ""
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(k8sClient.Delete(context.Background(), actionsSecret)).Should(Succeed())
+			Eventually(func() bool {
+				return isGone(key, &v1alpha1.ActionsSecret{})
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+
+	Context("Run a new ActionsSecret", func() {
+		It("Should upload and delete the secrets", func() {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-actions-source", Namespace: "default"},
+				Data:       map[string][]byte{"api-token": []byte("value")},
+			}
+			Expect(k8sClient.Create(context.Background(), secret)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), secret)).Should(Succeed())
+			}()
+
+			key := types.NamespacedName{Name: "test-actions-secret", Namespace: "default"}
+			actionsSecret := &v1alpha1.ActionsSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: v1alpha1.ActionsSecretSpec{
+					Organization: "awsctrl",
+					SecretRef:    v1alpha1.ActionsSecretSource{Name: secret.Name},
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), actionsSecret)).Should(Succeed())
+
+			By("Describing ActionsSecret Finalizers")
+			Eventually(func() bool {
+				a := &v1alpha1.ActionsSecret{}
+				k8sClient.Get(context.Background(), key, a)
+				return len(a.GetFinalizers()) == 1
+			}, timeout, interval).Should(BeTrue())
+
+			By("Describing Synced Status")
+			Eventually(func() bool {
+				a := &v1alpha1.ActionsSecret{}
+				k8sClient.Get(context.Background(), key, a)
+				return a.Status.Status == v1alpha1.SyncedStatus &&
+					len(a.Status.Secrets) == 1 && a.Status.Secrets[0] == "API_TOKEN"
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() bool {
+				return hasEvent(key.Namespace, key.Name, "Updated")
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(k8sClient.Delete(context.Background(), actionsSecret)).Should(Succeed())
+
+			By("Describing deletion state")
+			Eventually(func() bool {
+				return isGone(key, &v1alpha1.ActionsSecret{})
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() bool {
+				return hasEvent(key.Namespace, key.Name, "Deleted")
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+})
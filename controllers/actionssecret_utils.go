@@ -0,0 +1,283 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/policy"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+func (r *ActionsSecretReconciler) addFinalizer(ctx context.Context, actionsSecret *v1alpha1.ActionsSecret) error {
+	actionsSecret.ObjectMeta.Finalizers = append(actionsSecret.ObjectMeta.Finalizers, actionsSecretFinalizerName)
+	if err := r.Client.Update(ctx, actionsSecret); err != nil {
+		return err
+	}
+
+	return r.updateActionsSecretStatus(ctx, actionsSecret, v1alpha1.CreatingStatus)
+}
+
+func (r *ActionsSecretReconciler) handleDeletion(ctx context.Context, actionsSecret *v1alpha1.ActionsSecret) error {
+	if r.ActualDelete {
+		released := map[string]bool{}
+		for _, secret := range managedActionsSecrets(actionsSecret) {
+			org, repo := secret.Organization, secret.Repository
+			if _, ok := released[org]; !ok {
+				var err error
+				if released[org], err = releasedByPolicy(ctx, r.Client, r.Recorder, actionsSecret, actionsSecret.Namespace, org); err != nil {
+					return err
+				}
+			}
+			if released[org] {
+				continue
+			}
+
+			r.Log.Info("actual delete true", "deleting", fmt.Sprintf("%s/%s/%s", org, repo, secret.Name))
+			if err := r.deleteGitHubSecret(ctx, org, repo, secret.Name); err != nil {
+				return err
+			}
+			r.Recorder.Eventf(actionsSecret, corev1.EventTypeNormal, "Deleted", "deleted actions secret %s from %s", secret.Name, actionsSecretTarget(org, repo))
+		}
+	}
+
+	actionsSecret.ObjectMeta.Finalizers = removeString(actionsSecret.ObjectMeta.Finalizers, actionsSecretFinalizerName)
+	if err := r.Client.Update(context.Background(), actionsSecret); err != nil {
+		return err
+	}
+	return nil
+}
+
+// deleteGitHubSecret removes a repository secret, or an organization secret when repo is empty
+func (r *ActionsSecretReconciler) deleteGitHubSecret(ctx context.Context, org, repo, name string) error {
+	if repo != "" {
+		return r.GitClient.DeleteRepoSecret(ctx, org, repo, name)
+	}
+	return r.GitClient.DeleteOrgSecret(ctx, org, name)
+}
+
+// actionsSecretTarget names the repository or organization the secrets are uploaded to
+func actionsSecretTarget(org, repo string) string {
+	if repo == "" {
+		return org
+	}
+	return org + "/" + repo
+}
+
+// managedActionsSecrets lists every secret the status tracks, on the current
+// target and the ones retained on previous targets
+func managedActionsSecrets(actionsSecret *v1alpha1.ActionsSecret) []v1alpha1.RetainedActionsSecret {
+	secrets := make([]v1alpha1.RetainedActionsSecret, 0, len(actionsSecret.Status.Secrets)+len(actionsSecret.Status.RetainedSecrets))
+	for _, name := range actionsSecret.Status.Secrets {
+		secrets = append(secrets, v1alpha1.RetainedActionsSecret{
+			Organization: actionsSecret.Status.GitHubOrganization,
+			Repository:   actionsSecret.Status.GitHubRepository,
+			Name:         name,
+		})
+	}
+	return append(secrets, actionsSecret.Status.RetainedSecrets...)
+}
+
+// staleActionsSecrets lists the tracked secrets that are no longer part of the
+// source or are not on the target
+func staleActionsSecrets(actionsSecret *v1alpha1.ActionsSecret, org, repo string, values map[string][]byte) []v1alpha1.RetainedActionsSecret {
+	var stale []v1alpha1.RetainedActionsSecret
+	seen := map[v1alpha1.RetainedActionsSecret]bool{}
+	for _, secret := range managedActionsSecrets(actionsSecret) {
+		if _, ok := values[actionsSecretName(secret.Name)]; ok && secret.Organization == org && secret.Repository == repo {
+			continue
+		}
+		if !seen[secret] {
+			seen[secret] = true
+			stale = append(stale, secret)
+		}
+	}
+	return stale
+}
+
+func (r *ActionsSecretReconciler) updateActionsSecretStatusDetails(ctx context.Context, actionsSecret *v1alpha1.ActionsSecret, org, repo, contentHash string, names []string, retained []v1alpha1.RetainedActionsSecret) error {
+	nsn := types.NamespacedName{Namespace: actionsSecret.Namespace, Name: actionsSecret.Name}
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var actionsSecret v1alpha1.ActionsSecret
+		if err := r.Client.Get(ctx, nsn, &actionsSecret); err != nil {
+			return err
+		}
+
+		actionsSecretCopy := actionsSecret.DeepCopy()
+		actionsSecretCopy.Status = v1alpha1.ActionsSecretStatus{
+			Status:             v1alpha1.SyncedStatus,
+			ContentHash:        contentHash,
+			Secrets:            names,
+			RetainedSecrets:    retained,
+			GitHubRepository:   repo,
+			GitHubOrganization: org,
+		}
+
+		return r.Client.Status().Update(ctx, actionsSecretCopy)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkPolicy reports whether the organization policies allow the target of
+// the secrets, a repository target is checked like the Repository itself.
+// Denied ActionsSecrets get the Denied status with the reason.
+func (r *ActionsSecretReconciler) checkPolicy(ctx context.Context, actionsSecret *v1alpha1.ActionsSecret, org string, repository *v1alpha1.Repository) (bool, error) {
+	var err error
+	if repository != nil {
+		err = policy.Check(ctx, r.Client, repository)
+	} else {
+		err = policy.CheckOrganization(ctx, r.Client, actionsSecret.Namespace, org)
+	}
+	reason, err := policyViolation(err)
+	if err != nil || reason == "" {
+		return err == nil, err
+	}
+
+	if actionsSecret.Status.Status != v1alpha1.DeniedStatus || actionsSecret.Status.Message != reason {
+		r.Recorder.Event(actionsSecret, corev1.EventTypeWarning, policyViolationReason, reason)
+	}
+	return false, r.updateActionsSecretMessage(ctx, actionsSecret, v1alpha1.DeniedStatus, reason)
+}
+
+// updateActionsSecretMessage records why the secrets can't be uploaded
+func (r *ActionsSecretReconciler) updateActionsSecretMessage(ctx context.Context, actionsSecret *v1alpha1.ActionsSecret, status v1alpha1.StatusReason, message string) error {
+	nsn := types.NamespacedName{Namespace: actionsSecret.Namespace, Name: actionsSecret.Name}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var actionsSecret v1alpha1.ActionsSecret
+		if err := r.Client.Get(ctx, nsn, &actionsSecret); err != nil {
+			return err
+		}
+
+		if actionsSecret.Status.Status == status && actionsSecret.Status.Message == message {
+			return nil // no need to update
+		}
+
+		actionsSecretCopy := actionsSecret.DeepCopy()
+		actionsSecretCopy.Status.Status = status
+		actionsSecretCopy.Status.Message = message
+
+		return r.Client.Status().Update(ctx, actionsSecretCopy)
+	})
+}
+
+func (r *ActionsSecretReconciler) updateActionsSecretStatus(ctx context.Context, actionsSecret *v1alpha1.ActionsSecret, status v1alpha1.StatusReason) error {
+	nsn := types.NamespacedName{Namespace: actionsSecret.Namespace, Name: actionsSecret.Name}
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var actionsSecret v1alpha1.ActionsSecret
+		if err := r.Client.Get(ctx, nsn, &actionsSecret); err != nil {
+			return err
+		}
+
+		if actionsSecret.Status.Status == status {
+			return nil // no need to update
+		}
+
+		actionsSecretCopy := actionsSecret.DeepCopy()
+		actionsSecretCopy.Status.Status = status
+		actionsSecretCopy.Status.Message = ""
+
+		return r.Client.Status().Update(ctx, actionsSecretCopy)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// actionsSecretValues selects the Secret data that is uploaded, keyed by Actions secret name
+func actionsSecretValues(actionsSecret *v1alpha1.ActionsSecret, secret *corev1.Secret) (map[string][]byte, error) {
+	keys := actionsSecret.Spec.SecretRef.Keys
+	if len(keys) == 0 {
+		for key := range secret.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	}
+
+	values := map[string][]byte{}
+	sources := map[string]string{}
+	for _, key := range keys {
+		value, ok := secret.Data[key]
+		if !ok {
+			return nil, fmt.Errorf("referenced Secret %q does not contain key %q", secret.Name, key)
+		}
+
+		name := actionsSecretName(key)
+		if err := validActionsSecretName(name); err != nil {
+			return nil, fmt.Errorf("key %q of Secret %q: %v", key, secret.Name, err)
+		}
+		if other, ok := sources[name]; ok {
+			return nil, fmt.Errorf("keys %q and %q of Secret %q are both uploaded as %q", other, key, secret.Name, name)
+		}
+		sources[name] = key
+		values[name] = value
+	}
+	return values, nil
+}
+
+var actionsSecretNamePattern = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+// actionsSecretName turns a Secret key into an Actions secret name, GitHub
+// stores the names upper-cased and doesn't allow . or -
+func actionsSecretName(key string) string {
+	return strings.NewReplacer(".", "_", "-", "_").Replace(strings.ToUpper(key))
+}
+
+// validActionsSecretName checks the name against the rules of GitHub
+func validActionsSecretName(name string) error {
+	if !actionsSecretNamePattern.MatchString(name) {
+		return fmt.Errorf("actions secret name %q may only contain letters, numbers and _ and must not start with a number", name)
+	}
+	if strings.HasPrefix(name, "GITHUB_") {
+		return fmt.Errorf("actions secret name %q must not start with GITHUB_", name)
+	}
+	return nil
+}
+
+// actionsSecretHash hashes the values together with their target so a change
+// to either results in a re-upload
+func actionsSecretHash(org, repo, visibility string, selectedRepositoryIDs []int64, values map[string][]byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s/%s\x00%s\x00%v\x00", org, repo, visibility, selectedRepositoryIDs)
+	for _, name := range sortedKeys(values) {
+		fmt.Fprintf(h, "%s\x00%d\x00", name, len(values[name]))
+		h.Write(values[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func sortedKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+)
+
+// plannedReasons are the event reasons of changes made on GitHub
+var plannedReasons = map[string]bool{
+	"Created":     true,
+	"Updated":     true,
+	"Deleted":     true,
+	"Renamed":     true,
+	"Transferred": true,
+	"Imported":    true,
+	"Exported":    true,
+}
+
+// DryRunRecorder wraps the recorder so events of changes made on GitHub are
+// recorded as Planned, the dry-run git client skips these changes
+func DryRunRecorder(recorder record.EventRecorder) record.EventRecorder {
+	return &dryRunRecorder{EventRecorder: recorder}
+}
+
+type dryRunRecorder struct {
+	record.EventRecorder
+}
+
+func (in *dryRunRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if eventtype == corev1.EventTypeNormal && plannedReasons[reason] {
+		reason, message = "Planned", fmt.Sprintf("%s (dry-run, GitHub was not changed)", message)
+	}
+	in.EventRecorder.Event(object, eventtype, reason, message)
+}
+
+func (in *dryRunRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	in.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// updateRepositoryPlan stores the changes the dry-run client skipped
+func (r *RepositoryReconciler) updateRepositoryPlan(ctx context.Context, repository *v1alpha1.Repository, plan *git.Plan) error {
+	nsn := types.NamespacedName{Namespace: repository.Namespace, Name: repository.Name}
+	changes := plan.Changes()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var repo v1alpha1.Repository
+		if err := r.Client.Get(ctx, nsn, &repo); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		if reflect.DeepEqual(repo.Status.PlannedChanges, changes) {
+			return nil // no need to update
+		}
+
+		repoCopy := repo.DeepCopy()
+		repoCopy.Status.PlannedChanges = changes
+
+		return r.Client.Status().Update(ctx, repoCopy)
+	})
+}
+
+// updateKeyPlan stores the changes the dry-run client skipped
+func (r *KeyReconciler) updateKeyPlan(ctx context.Context, key *v1alpha1.Key, plan *git.Plan) error {
+	nsn := types.NamespacedName{Namespace: key.Namespace, Name: key.Name}
+	changes := plan.Changes()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var key v1alpha1.Key
+		if err := r.Client.Get(ctx, nsn, &key); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		if reflect.DeepEqual(key.Status.PlannedChanges, changes) {
+			return nil // no need to update
+		}
+
+		keyCopy := key.DeepCopy()
+		keyCopy.Status.PlannedChanges = changes
+
+		return r.Client.Status().Update(ctx, keyCopy)
+	})
+}
+
+// updateActionsSecretPlan stores the changes the dry-run client skipped
+func (r *ActionsSecretReconciler) updateActionsSecretPlan(ctx context.Context, actionsSecret *v1alpha1.ActionsSecret, plan *git.Plan) error {
+	nsn := types.NamespacedName{Namespace: actionsSecret.Namespace, Name: actionsSecret.Name}
+	changes := plan.Changes()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var actionsSecret v1alpha1.ActionsSecret
+		if err := r.Client.Get(ctx, nsn, &actionsSecret); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		if reflect.DeepEqual(actionsSecret.Status.PlannedChanges, changes) {
+			return nil // no need to update
+		}
+
+		actionsSecretCopy := actionsSecret.DeepCopy()
+		actionsSecretCopy.Status.PlannedChanges = changes
+
+		return r.Client.Status().Update(ctx, actionsSecretCopy)
+	})
+}
+
+// updateRepositoryFilePlan stores the changes the dry-run client skipped
+func (r *RepositoryFileReconciler) updateRepositoryFilePlan(ctx context.Context, file *v1alpha1.RepositoryFile, plan *git.Plan) error {
+	nsn := types.NamespacedName{Namespace: file.Namespace, Name: file.Name}
+	changes := plan.Changes()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var file v1alpha1.RepositoryFile
+		if err := r.Client.Get(ctx, nsn, &file); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		if reflect.DeepEqual(file.Status.PlannedChanges, changes) {
+			return nil // no need to update
+		}
+
+		fileCopy := file.DeepCopy()
+		fileCopy.Status.PlannedChanges = changes
+
+		return r.Client.Status().Update(ctx, fileCopy)
+	})
+}
+
+// updateCustomPropertyPlan stores the changes the dry-run client skipped
+func (r *OrganizationCustomPropertyReconciler) updateCustomPropertyPlan(ctx context.Context, property *v1alpha1.OrganizationCustomProperty, plan *git.Plan) error {
+	nsn := types.NamespacedName{Name: property.Name}
+	changes := plan.Changes()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var property v1alpha1.OrganizationCustomProperty
+		if err := r.Client.Get(ctx, nsn, &property); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		if reflect.DeepEqual(property.Status.PlannedChanges, changes) {
+			return nil // no need to update
+		}
+
+		propertyCopy := property.DeepCopy()
+		propertyCopy.Status.PlannedChanges = changes
+
+		return r.Client.Status().Update(ctx, propertyCopy)
+	})
+}
@@ -27,10 +27,16 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	v1alpha1 "go.hein.dev/github-controller/api/v1alpha1"
 	"go.hein.dev/github-controller/git"
@@ -48,15 +54,30 @@ type KeyReconciler struct {
 	Scheme       *runtime.Scheme
 	GitClient    git.Client
 	ActualDelete bool
+
+	// Recorder records an event for every change made on GitHub
+	Recorder record.EventRecorder
+
+	// ReleasePausedOnDelete removes the finalizer of paused objects that are
+	// deleted instead of waiting for them to be resumed
+	ReleasePausedOnDelete bool
+
+	// GitHubEvents optionally triggers reconciles from GitHub webhook deliveries
+	GitHubEvents <-chan event.GenericEvent
+
+	// Selector optionally limits the reconciled Keys to the ones with matching labels
+	Selector labels.Selector
 }
 
 // +kubebuilder:rbac:groups=github.go.hein.dev,resources=keys,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=github.go.hein.dev,resources=keys/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=github.go.hein.dev,resources=organizationpolicies,verbs=get;list;watch
 
 func (r *KeyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	var requeueAfter = 2 * time.Second
-	ctx := context.Background()
+	ctx, plan := git.WithPlan(context.Background())
 	log := r.Log.WithValues("key", req.NamespacedName)
 
 	var key v1alpha1.Key
@@ -66,6 +87,35 @@ func (r *KeyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		}
 		return ctrl.Result{}, err
 	}
+	if !selected(r.Selector, &key) {
+		return ctrl.Result{}, nil
+	}
+
+	// the changes skipped by the dry-run client are stored whichever way the reconcile ends
+	defer func() {
+		if err := r.updateKeyPlan(ctx, &key, plan); err != nil {
+			log.Error(err, "unable to update planned changes")
+		}
+	}()
+
+	// paused keys are left alone on GitHub
+	paused, pauseErr := r.handlePaused(ctx, &key)
+	if pauseErr != nil {
+		return ctrl.Result{}, pauseErr
+	}
+	if paused {
+		log.Info("reconciliation paused", "name", key.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// a changed reconcile-requested-at annotation reads everything fresh from GitHub
+	// and uploads the exported private key again
+	requestedAt := v1alpha1.ReconcileRequestedAt(&key)
+	forced := requestedAt != "" && requestedAt != key.Status.LastHandledReconcileAt
+	if forced {
+		log.Info("reconcile requested", "requestedAt", requestedAt)
+		ctx = git.WithFreshReads(ctx)
+	}
 
 	// handle finalizers before any other reconcile logic can fail
 	if !key.ObjectMeta.DeletionTimestamp.IsZero() &&
@@ -97,8 +147,14 @@ func (r *KeyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 				r.updateKeyStatus(ctx, &key, v1alpha1.DeletingStatus)
 				err = r.GitClient.DeleteKey(ctx, key.Status.GitHubOrganization, key.Status.GitHubRepository, key.Status.GitHubKeyID)
 				if err != nil {
+					r.Recorder.Event(&key, corev1.EventTypeWarning, "DeleteFailed", err.Error())
 					return ctrl.Result{RequeueAfter: requeueAfter}, err
 				}
+				r.Recorder.Eventf(&key, corev1.EventTypeNormal, "Deleted", "deleted key %d, secret %s no longer exists", key.Status.GitHubKeyID, secretRef)
+				// the dry-run client only planned the deletion, keep the key and its status
+				if len(plan.Changes()) > 0 {
+					return ctrl.Result{}, nil
+				}
 			}
 
 			r.updateKeyStatus(ctx, &key, v1alpha1.CreatingStatus)
@@ -209,6 +265,16 @@ func (r *KeyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
+	// keys of repositories the organization policies don't allow are left alone on GitHub
+	allowed, policyErr := r.checkPolicy(ctx, &key, &repository)
+	if policyErr != nil {
+		return ctrl.Result{}, policyErr
+	}
+	if !allowed {
+		log.Info("key not allowed by organization policies", "name", key.Name)
+		return ctrl.Result{}, nil
+	}
+
 	// Repo and Secret are both ready, add finalizer for github-Delete before doing github-Create
 	if key.ObjectMeta.DeletionTimestamp.IsZero() &&
 		!containsString(key.GetFinalizers(), keyFinalizerName) {
@@ -225,9 +291,16 @@ func (r *KeyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	createGHKeyAndUpdate := func() (ctrl.Result, error) {
 		r.updateKeyStatus(ctx, &key, v1alpha1.CreatingStatus)
-		if ghKey, err = r.GitClient.CreateKey(ctx, repository.Spec.Organization, repository.GetName(), &key, &secret); err != nil {
+		if ghKey, err = r.GitClient.CreateKey(ctx, repository.Spec.Organization, repository.GitHubName(), &key, &secret); err != nil {
+			r.Recorder.Event(&key, corev1.EventTypeWarning, "CreateFailed", err.Error())
 			return ctrl.Result{RequeueAfter: requeueAfter}, err
 		}
+		r.Recorder.Eventf(&key, corev1.EventTypeNormal, "Created", "created key %d in %s/%s", ghKey.GetID(), repository.Spec.Organization, repository.GitHubName())
+
+		// the dry-run client only planned the key, it has no ID to record
+		if len(plan.Changes()) > 0 {
+			return ctrl.Result{}, nil
+		}
 
 		err = r.updateKeyStatusDetails(ctx, &repository, ghKey, &key)
 		if err != nil {
@@ -241,13 +314,15 @@ func (r *KeyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return createGHKeyAndUpdate()
 	}
 
-	ghKey, resp, err = r.GitClient.GetKey(ctx, repository.Spec.Organization, repository.GetName(), key.Status.GitHubKeyID)
+	ghKey, resp, err = r.GitClient.GetKey(ctx, repository.Spec.Organization, repository.GitHubName(), key.Status.GitHubKeyID)
 	if err != nil && isNotFound(resp) {
 		log.Info("expected key not found, creating new key in GitHub", "missingID", key.Status.GitHubKeyID)
+		driftCorrections.WithLabelValues("Key", "key").Inc()
 		return createGHKeyAndUpdate()
 		// note: this can occur on a re-sync despite there being no watch on the GitHub API objects
 	} else if err != nil {
 		log.Error(err, "error fetching key from GitHub")
+		r.Recorder.Event(&key, corev1.EventTypeWarning, "SyncFailed", err.Error())
 		return ctrl.Result{RequeueAfter: requeueAfter}, err
 	}
 
@@ -255,20 +330,81 @@ func (r *KeyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	recreateGHKey := (strings.TrimSpace(ghKey.GetKey()) != strings.TrimSpace(key.Status.PublicKey) ||
 		ghKey.GetReadOnly() != key.Spec.ReadOnly)
 	if recreateGHKey {
+		r.Recorder.Eventf(&key, corev1.EventTypeWarning, "Mismatch", "key %d does not match the spec, re-creating it", key.Status.GitHubKeyID)
 		r.updateKeyStatus(ctx, &key, v1alpha1.DeletingStatus)
-		err = r.GitClient.DeleteKey(ctx, repository.Spec.Organization, repository.GetName(), key.Status.GitHubKeyID)
+		err = r.GitClient.DeleteKey(ctx, repository.Spec.Organization, repository.GitHubName(), key.Status.GitHubKeyID)
 		if err != nil {
+			r.Recorder.Event(&key, corev1.EventTypeWarning, "DeleteFailed", err.Error())
 			return ctrl.Result{RequeueAfter: requeueAfter}, err
 		}
+		driftCorrections.WithLabelValues("Key", "key").Inc()
+		// the dry-run client only planned the deletion, the key would be re-created forever
+		if len(plan.Changes()) > 0 {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	// the deploy key is in place, export the private key if requested
+	ready, err := r.reconcileExport(ctx, &key, &secret, forced, plan)
+	if err != nil {
+		r.Recorder.Event(&key, corev1.EventTypeWarning, "ExportFailed", err.Error())
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
+	// the dry-run client only planned the export, the reconcile is not handled yet
+	if len(plan.Changes()) > 0 {
+		return ctrl.Result{}, nil
+	}
+	if !ready {
+		log.Info("export repository not yet synced", "exportRepository", key.Spec.Export.RepositoryRef)
 		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
+	if requestedAt != key.Status.LastHandledReconcileAt {
+		if err := r.updateKeyLastHandledReconcileAt(ctx, &key, requestedAt); err != nil {
+			return ctrl.Result{RequeueAfter: requeueAfter}, err
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
 
 func (r *KeyReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("key-controller")
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.Key{}).
 		Owns(&corev1.Secret{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &v1alpha1.OrganizationPolicy{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.policyToKeys),
+		})
+
+	if r.GitHubEvents != nil {
+		builder = builder.Watches(&source.Channel{Source: r.GitHubEvents}, &handler.EnqueueRequestForObject{})
+	}
+	if r.Selector != nil {
+		builder = builder.WithEventFilter(selectorPredicate(r.Selector, &v1alpha1.Key{}))
+	}
+
+	return builder.Complete(r)
+}
+
+// policyToKeys queues every Key, a policy can allow or deny the repositories of
+// any of them
+func (r *KeyReconciler) policyToKeys(obj handler.MapObject) []reconcile.Request {
+	var list v1alpha1.KeyList
+	if err := r.Client.List(context.Background(), &list); err != nil {
+		r.Log.Error(err, "unable to list keys", "organizationPolicy", obj.Meta.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, key := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: key.Namespace, Name: key.Name},
+		})
+	}
+	return requests
 }
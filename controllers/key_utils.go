@@ -22,6 +22,10 @@ import (
 
 	"github.com/google/go-github/v28/github"
 	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+	"go.hein.dev/github-controller/policy"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
@@ -44,14 +48,43 @@ func (r *KeyReconciler) handleDeletion(ctx context.Context, key *v1alpha1.Key) e
 	if keyID != 0 && repo != "" && org != "" {
 		_, resp, err := r.GitClient.GetKey(ctx, org, repo, keyID)
 		if err != nil && !isNotFound(resp) {
+			r.Recorder.Event(key, corev1.EventTypeWarning, "DeleteFailed", err.Error())
 			return err
 		}
 
+		released := false
 		if r.ActualDelete {
+			if released, err = releasedByPolicy(ctx, r.Client, r.Recorder, key, key.Namespace, org); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case released:
+		case r.ActualDelete:
 			r.Log.Info("actual delete true", "deleting", fmt.Sprintf("%s/%s/%s", org, repo, key.Name))
 			if err := r.GitClient.DeleteKey(ctx, org, repo, keyID); err != nil {
+				r.Recorder.Event(key, corev1.EventTypeWarning, "DeleteFailed", err.Error())
 				return err
 			}
+			r.Recorder.Eventf(key, corev1.EventTypeNormal, "Deleted", "deleted key %d from %s/%s", keyID, org, repo)
+		default:
+			r.Recorder.Eventf(key, corev1.EventTypeNormal, "DeleteSkipped", "kept key %d in %s/%s, --actual-delete is off", keyID, org, repo)
+		}
+	}
+
+	if export := key.Status.Export; export != nil && r.ActualDelete {
+		released, err := releasedByPolicy(ctx, r.Client, r.Recorder, key, key.Namespace, export.GitHubOrganization)
+		if err != nil {
+			return err
+		}
+		if !released {
+			r.Log.Info("actual delete true", "deleting", fmt.Sprintf("%s/%s/%s", export.GitHubOrganization, export.GitHubRepository, export.SecretName))
+			if err := r.GitClient.DeleteRepoSecret(ctx, export.GitHubOrganization, export.GitHubRepository, export.SecretName); err != nil {
+				r.Recorder.Event(key, corev1.EventTypeWarning, "DeleteFailed", err.Error())
+				return err
+			}
+			r.Recorder.Eventf(key, corev1.EventTypeNormal, "Deleted", "deleted exported secret %s from %s/%s", export.SecretName, export.GitHubOrganization, export.GitHubRepository)
 		}
 	}
 
@@ -62,6 +95,44 @@ func (r *KeyReconciler) handleDeletion(ctx context.Context, key *v1alpha1.Key) e
 	return nil
 }
 
+// checkPolicy reports whether the organization policies allow the repository
+// of the key and the repository its private key is exported to, and reflects
+// it in the Allowed condition like for repositories
+func (r *KeyReconciler) checkPolicy(ctx context.Context, key *v1alpha1.Key, repository *v1alpha1.Repository) (bool, error) {
+	reason, err := policyViolation(policy.Check(ctx, r.Client, repository))
+	if err == nil && reason == "" && key.Spec.Export != nil {
+		reason, err = policyViolation(policy.CheckRepositoryRef(ctx, r.Client, key.Namespace, key.Spec.Export.RepositoryRef))
+	}
+	if err != nil {
+		return false, err
+	}
+
+	denied := reason != ""
+	current := v1alpha1.FindCondition(key.Status.Conditions, v1alpha1.AllowedCondition)
+	switch {
+	case denied && (current == nil || current.Status != corev1.ConditionFalse || current.Message != reason):
+		v1alpha1.SetCondition(&key.Status.Conditions, v1alpha1.Condition{
+			Type:    v1alpha1.AllowedCondition,
+			Status:  corev1.ConditionFalse,
+			Reason:  policyViolationReason,
+			Message: reason,
+		})
+		r.Recorder.Event(key, corev1.EventTypeWarning, policyViolationReason, reason)
+	case !denied && current != nil && current.Status != corev1.ConditionTrue:
+		v1alpha1.SetCondition(&key.Status.Conditions, v1alpha1.Condition{
+			Type:    v1alpha1.AllowedCondition,
+			Status:  corev1.ConditionTrue,
+			Reason:  policyAllowedReason,
+			Message: "the organization policies allow the key",
+		})
+		r.Recorder.Event(key, corev1.EventTypeNormal, policyAllowedReason, "the organization policies allow the key")
+	default:
+		return !denied, nil
+	}
+
+	return !denied, r.updateKeyConditions(ctx, key)
+}
+
 func (r *KeyReconciler) updateKeyStatusCreatingPublicKey(ctx context.Context, nsn types.NamespacedName, publicKey string) error {
 	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		var key v1alpha1.Key
@@ -73,6 +144,8 @@ func (r *KeyReconciler) updateKeyStatusCreatingPublicKey(ctx context.Context, ns
 		keyCopy.Status = v1alpha1.KeyStatus{
 			Status:    v1alpha1.CreatingStatus,
 			PublicKey: publicKey,
+			// keep track of the previous export so it is rotated along with the key
+			Export: key.Status.Export,
 		}
 
 		return r.Client.Status().Update(ctx, keyCopy)
@@ -93,9 +166,9 @@ func (r *KeyReconciler) updateKeyStatusDetails(ctx context.Context, repo *v1alph
 
 		keyCopy := key.DeepCopy()
 		keyCopy.Status.Status = v1alpha1.SyncedStatus
-		keyCopy.Status.URL = fmt.Sprintf("https://github.com/%s/%s/settings/keys", repo.Spec.Organization, repo.Name)
+		keyCopy.Status.URL = fmt.Sprintf("https://github.com/%s/%s/settings/keys", repo.Spec.Organization, repo.GitHubName())
 		keyCopy.Status.GitHubKeyID = ghKey.GetID()
-		keyCopy.Status.GitHubRepository = repo.GetName()
+		keyCopy.Status.GitHubRepository = repo.GitHubName()
 		keyCopy.Status.GitHubOrganization = repo.Spec.Organization
 
 		return r.Client.Status().Update(ctx, keyCopy)
@@ -105,6 +178,121 @@ func (r *KeyReconciler) updateKeyStatusDetails(ctx context.Context, repo *v1alph
 	return nil
 }
 
+// reconcileExport uploads the private key to the Actions secret of the export
+// Repository whenever the target or the key changes or the upload is forced,
+// it reports false while the export Repository is not synced yet. Changes the
+// dry-run client only planned are not recorded in the status.
+func (r *KeyReconciler) reconcileExport(ctx context.Context, key *v1alpha1.Key, secret *corev1.Secret, force bool, plan *git.Plan) (bool, error) {
+	current := key.Status.Export
+	export := key.Spec.Export
+
+	if export == nil {
+		if current == nil {
+			return true, nil
+		}
+		if err := r.deleteExport(ctx, key, current); err != nil {
+			return false, err
+		}
+		if len(plan.Changes()) > 0 {
+			return true, nil
+		}
+		return true, r.updateKeyStatusExport(ctx, key, nil)
+	}
+
+	var repository v1alpha1.Repository
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: export.RepositoryRef, Namespace: key.Namespace}, &repository); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if repository.Status.Status != v1alpha1.SyncedStatus {
+		return false, nil
+	}
+
+	desired := &v1alpha1.KeyExportStatus{
+		GitHubRepository:   repository.GitHubName(),
+		GitHubOrganization: repository.Spec.Organization,
+		SecretName:         export.SecretName,
+		PublicKey:          key.Status.PublicKey,
+	}
+	if current != nil && *current == *desired && !force {
+		return true, nil
+	}
+
+	r.Log.Info("exporting private key", "exporting", fmt.Sprintf("%s/%s/%s", desired.GitHubOrganization, desired.GitHubRepository, desired.SecretName))
+	if err := r.GitClient.CreateOrUpdateRepoSecret(ctx, desired.GitHubOrganization, desired.GitHubRepository, desired.SecretName, secret.Data["identity"]); err != nil {
+		return false, err
+	}
+	r.Recorder.Eventf(key, corev1.EventTypeNormal, "Exported", "exported private key to secret %s in %s/%s", desired.SecretName, desired.GitHubOrganization, desired.GitHubRepository)
+
+	// the target moved, remove the private key from the previous one
+	if current != nil && (current.GitHubOrganization != desired.GitHubOrganization ||
+		current.GitHubRepository != desired.GitHubRepository ||
+		current.SecretName != desired.SecretName) {
+		if err := r.deleteExport(ctx, key, current); err != nil {
+			return false, err
+		}
+	}
+
+	if len(plan.Changes()) > 0 {
+		return true, nil
+	}
+	return true, r.updateKeyStatusExport(ctx, key, desired)
+}
+
+// deleteExport removes a previously exported private key, it is kept when
+// --actual-delete is off
+func (r *KeyReconciler) deleteExport(ctx context.Context, key *v1alpha1.Key, export *v1alpha1.KeyExportStatus) error {
+	if !r.ActualDelete {
+		r.Recorder.Eventf(key, corev1.EventTypeNormal, "DeleteSkipped", "kept exported secret %s in %s/%s, --actual-delete is off", export.SecretName, export.GitHubOrganization, export.GitHubRepository)
+		return nil
+	}
+
+	r.Log.Info("removing exported private key", "deleting", fmt.Sprintf("%s/%s/%s", export.GitHubOrganization, export.GitHubRepository, export.SecretName))
+	if err := r.GitClient.DeleteRepoSecret(ctx, export.GitHubOrganization, export.GitHubRepository, export.SecretName); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(key, corev1.EventTypeNormal, "Deleted", "removed exported secret %s from %s/%s", export.SecretName, export.GitHubOrganization, export.GitHubRepository)
+	return nil
+}
+
+func (r *KeyReconciler) updateKeyStatusExport(ctx context.Context, key *v1alpha1.Key, export *v1alpha1.KeyExportStatus) error {
+	nsn := types.NamespacedName{Namespace: key.Namespace, Name: key.Name}
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var key v1alpha1.Key
+		if err := r.Client.Get(ctx, nsn, &key); err != nil {
+			return err
+		}
+
+		keyCopy := key.DeepCopy()
+		keyCopy.Status.Export = export
+
+		return r.Client.Status().Update(ctx, keyCopy)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// updateKeyLastHandledReconcileAt records the handled reconcile-requested-at annotation
+func (r *KeyReconciler) updateKeyLastHandledReconcileAt(ctx context.Context, key *v1alpha1.Key, requestedAt string) error {
+	nsn := types.NamespacedName{Namespace: key.Namespace, Name: key.Name}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var key v1alpha1.Key
+		if err := r.Client.Get(ctx, nsn, &key); err != nil {
+			return err
+		}
+
+		keyCopy := key.DeepCopy()
+		keyCopy.Status.LastHandledReconcileAt = requestedAt
+
+		return r.Client.Status().Update(ctx, keyCopy)
+	})
+}
+
 func (r *KeyReconciler) updateKeyStatus(ctx context.Context, key *v1alpha1.Key, status v1alpha1.StatusReason) error {
 	nsn := types.NamespacedName{Namespace: key.Namespace, Name: key.Name}
 
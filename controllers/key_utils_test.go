@@ -0,0 +1,183 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+)
+
+// secretClient records the Actions secrets written to the fake GitHub client
+type secretClient struct {
+	git.Client
+	calls []string
+}
+
+func (c *secretClient) CreateOrUpdateRepoSecret(ctx context.Context, org, repoName, name string, value []byte) error {
+	c.calls = append(c.calls, "put "+org+"/"+repoName+"/"+name)
+	return c.Client.CreateOrUpdateRepoSecret(ctx, org, repoName, name, value)
+}
+
+func (c *secretClient) DeleteRepoSecret(ctx context.Context, org, repoName, name string) error {
+	c.calls = append(c.calls, "delete "+org+"/"+repoName+"/"+name)
+	return c.Client.DeleteRepoSecret(ctx, org, repoName, name)
+}
+
+func TestReconcileExport(t *testing.T) {
+	synced := &v1alpha1.KeyExportStatus{GitHubOrganization: "org", GitHubRepository: "deploy", SecretName: "DEPLOY_KEY", PublicKey: "ssh-ed25519 AAAA"}
+	previous := &v1alpha1.KeyExportStatus{GitHubOrganization: "org", GitHubRepository: "old", SecretName: "DEPLOY_KEY", PublicKey: "ssh-ed25519 AAAA"}
+
+	tests := []struct {
+		name           string
+		export         *v1alpha1.KeyExport
+		current        *v1alpha1.KeyExportStatus
+		force          bool
+		actualDelete   bool
+		dryRun         bool
+		expectedDone   bool
+		expectedCalls  []string
+		expectedStatus *v1alpha1.KeyExportStatus
+	}{
+		{
+			name:   "repository not found",
+			export: &v1alpha1.KeyExport{RepositoryRef: "missing", SecretName: "DEPLOY_KEY"},
+		},
+		{
+			name:   "repository not synced",
+			export: &v1alpha1.KeyExport{RepositoryRef: "pending", SecretName: "DEPLOY_KEY"},
+		},
+		{
+			name:           "exports",
+			export:         &v1alpha1.KeyExport{RepositoryRef: "deploy", SecretName: "DEPLOY_KEY"},
+			expectedDone:   true,
+			expectedCalls:  []string{"put org/deploy/DEPLOY_KEY"},
+			expectedStatus: synced,
+		},
+		{
+			name:           "in sync",
+			export:         &v1alpha1.KeyExport{RepositoryRef: "deploy", SecretName: "DEPLOY_KEY"},
+			current:        synced,
+			expectedDone:   true,
+			expectedStatus: synced,
+		},
+		{
+			name:           "forced",
+			export:         &v1alpha1.KeyExport{RepositoryRef: "deploy", SecretName: "DEPLOY_KEY"},
+			current:        synced,
+			force:          true,
+			expectedDone:   true,
+			expectedCalls:  []string{"put org/deploy/DEPLOY_KEY"},
+			expectedStatus: synced,
+		},
+		{
+			name:           "moved",
+			export:         &v1alpha1.KeyExport{RepositoryRef: "deploy", SecretName: "DEPLOY_KEY"},
+			current:        previous,
+			actualDelete:   true,
+			expectedDone:   true,
+			expectedCalls:  []string{"put org/deploy/DEPLOY_KEY", "delete org/old/DEPLOY_KEY"},
+			expectedStatus: synced,
+		},
+		{
+			name:           "moved without actual delete",
+			export:         &v1alpha1.KeyExport{RepositoryRef: "deploy", SecretName: "DEPLOY_KEY"},
+			current:        previous,
+			expectedDone:   true,
+			expectedCalls:  []string{"put org/deploy/DEPLOY_KEY"},
+			expectedStatus: synced,
+		},
+		{
+			name:          "removed",
+			current:       synced,
+			actualDelete:  true,
+			expectedDone:  true,
+			expectedCalls: []string{"delete org/deploy/DEPLOY_KEY"},
+		},
+		{
+			name:           "planned",
+			export:         &v1alpha1.KeyExport{RepositoryRef: "deploy", SecretName: "DEPLOY_KEY"},
+			current:        previous,
+			actualDelete:   true,
+			dryRun:         true,
+			expectedDone:   true,
+			expectedStatus: previous,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := &v1alpha1.Key{
+				ObjectMeta: metav1.ObjectMeta{Name: "key", Namespace: "default"},
+				Spec:       v1alpha1.KeySpec{Export: tt.export},
+				Status:     v1alpha1.KeyStatus{PublicKey: "ssh-ed25519 AAAA", Export: tt.current},
+			}
+			deploy := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "default"},
+				Spec:       v1alpha1.RepositorySpec{Organization: "org"},
+				Status:     v1alpha1.RepositoryStatus{Status: v1alpha1.SyncedStatus},
+			}
+			pending := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"},
+				Spec:       v1alpha1.RepositorySpec{Organization: "org"},
+			}
+			secret := &corev1.Secret{Data: map[string][]byte{"identity": []byte("private key")}}
+
+			gitClient := &secretClient{Client: git.TestClient()}
+			r := &KeyReconciler{
+				Client:       fake.NewFakeClientWithScheme(testScheme(t), key.DeepCopy(), deploy, pending),
+				Log:          logf.NullLogger{},
+				GitClient:    gitClient,
+				ActualDelete: tt.actualDelete,
+				Recorder:     record.NewFakeRecorder(100),
+			}
+			ctx, plan := git.WithPlan(context.Background())
+			if tt.dryRun {
+				r.GitClient = git.DryRun(gitClient, logf.NullLogger{})
+			}
+
+			done, err := r.reconcileExport(ctx, key, secret, tt.force, plan)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if done != tt.expectedDone {
+				t.Errorf("expected done %v, got %v", tt.expectedDone, done)
+			}
+			if !reflect.DeepEqual(gitClient.calls, tt.expectedCalls) {
+				t.Errorf("expected %v, got %v", tt.expectedCalls, gitClient.calls)
+			}
+
+			var stored v1alpha1.Key
+			if err := r.Client.Get(ctx, types.NamespacedName{Name: "key", Namespace: "default"}, &stored); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(stored.Status.Export, tt.expectedStatus) {
+				t.Errorf("expected status %+v, got %+v", tt.expectedStatus, stored.Status.Export)
+			}
+		})
+	}
+}
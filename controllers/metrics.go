@@ -0,0 +1,101 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+)
+
+var (
+	driftCorrections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "github_controller_drift_corrections_total",
+		Help: "Number of changes made on GitHub to bring it back in line with the spec by kind and resource.",
+	}, []string{"kind", "resource"})
+
+	managedObjectsDesc = prometheus.NewDesc(
+		"github_controller_managed_objects",
+		"Number of managed objects by kind and status.",
+		[]string{"kind", "status"}, nil,
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(driftCorrections)
+}
+
+// StatusCollector reports the managed Repository and Key objects by their
+// status every time the metrics are scraped
+type StatusCollector struct {
+	Reader client.Reader
+
+	// Selector optionally limits the reported objects to the ones with matching labels
+	Selector labels.Selector
+}
+
+// Describe implements prometheus.Collector
+func (c *StatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- managedObjectsDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *StatusCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+	var opts []client.ListOption
+	if c.Selector != nil {
+		opts = append(opts, client.MatchingLabelsSelector{Selector: c.Selector})
+	}
+
+	var repositories v1alpha1.RepositoryList
+	if err := c.Reader.List(ctx, &repositories, opts...); err != nil {
+		ch <- prometheus.NewInvalidMetric(managedObjectsDesc, err)
+		return
+	}
+	counts := map[v1alpha1.StatusReason]int{}
+	for _, repository := range repositories.Items {
+		counts[repository.Status.Status]++
+	}
+	collectStatusCounts(ch, "Repository", counts)
+
+	var keys v1alpha1.KeyList
+	if err := c.Reader.List(ctx, &keys, opts...); err != nil {
+		ch <- prometheus.NewInvalidMetric(managedObjectsDesc, err)
+		return
+	}
+	counts = map[v1alpha1.StatusReason]int{}
+	for _, key := range keys.Items {
+		counts[key.Status.Status]++
+	}
+	collectStatusCounts(ch, "Key", counts)
+}
+
+// collectStatusCounts sends a gauge for every status, objects that were never
+// reconciled are reported with the Pending status
+func collectStatusCounts(ch chan<- prometheus.Metric, kind string, counts map[v1alpha1.StatusReason]int) {
+	for status, count := range counts {
+		if status == "" {
+			status = "Pending"
+		}
+		ch <- prometheus.MustNewConstMetric(managedObjectsDesc, prometheus.GaugeValue, float64(count), kind, string(status))
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// NamespacedClient creates the client of a manager whose cache only watches
+// some namespaces, it is a manager.NewClientFunc. The namespaced caches can't
+// serve cluster scoped kinds like the OrganizationPolicy, so those are read
+// from the API server.
+func NamespacedClient(c cache.Cache, config *rest.Config, options client.Options) (client.Client, error) {
+	api, err := client.New(config, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.DelegatingClient{
+		Reader: &clusterScopedReader{
+			cache:  c,
+			api:    api,
+			scheme: options.Scheme,
+			mapper: options.Mapper,
+		},
+		Writer:       api,
+		StatusClient: api,
+	}, nil
+}
+
+// clusterScopedReader reads the cluster scoped kinds from the API server and
+// everything else from the cache
+type clusterScopedReader struct {
+	cache  client.Reader
+	api    client.Reader
+	scheme *runtime.Scheme
+	mapper meta.RESTMapper
+}
+
+func (r *clusterScopedReader) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if r.clusterScoped(obj) {
+		return r.api.Get(ctx, key, obj)
+	}
+	return r.cache.Get(ctx, key, obj)
+}
+
+func (r *clusterScopedReader) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	if r.clusterScoped(list) {
+		return r.api.List(ctx, list, opts...)
+	}
+	return r.cache.List(ctx, list, opts...)
+}
+
+// clusterScoped looks up the scope of the kind of the object or list, unknown
+// kinds are left to the cache to report
+func (r *clusterScopedReader) clusterScoped(obj runtime.Object) bool {
+	gvk, err := apiutil.GVKForObject(obj, r.scheme)
+	if err != nil {
+		return false
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+
+	mapping, err := r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameRoot
+}
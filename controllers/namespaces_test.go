@@ -0,0 +1,132 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+)
+
+// recordingCache answers every read with an empty object and records it
+type recordingCache struct {
+	cache.Cache
+	reads []string
+}
+
+func (c *recordingCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	c.reads = append(c.reads, "get "+key.Name)
+	return nil
+}
+
+func (c *recordingCache) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	c.reads = append(c.reads, "list")
+	return nil
+}
+
+func testScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return scheme
+}
+
+func testMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(v1alpha1.GroupVersion.WithKind("Repository"), meta.RESTScopeNamespace)
+	mapper.Add(v1alpha1.GroupVersion.WithKind("OrganizationPolicy"), meta.RESTScopeRoot)
+	return mapper
+}
+
+func TestClusterScopedReader(t *testing.T) {
+	cached, api := &recordingCache{}, &recordingCache{}
+	reader := &clusterScopedReader{cache: cached, api: api, scheme: testScheme(t), mapper: testMapper()}
+	ctx := context.Background()
+
+	reads := []func() error{
+		func() error {
+			return reader.Get(ctx, client.ObjectKey{Namespace: "default", Name: "repo"}, &v1alpha1.Repository{})
+		},
+		func() error { return reader.List(ctx, &v1alpha1.RepositoryList{}) },
+		func() error {
+			return reader.Get(ctx, client.ObjectKey{Name: "orgname"}, &v1alpha1.OrganizationPolicy{})
+		},
+		func() error { return reader.List(ctx, &v1alpha1.OrganizationPolicyList{}) },
+		// kinds the mapper doesn't know are left to the cache
+		func() error {
+			return reader.Get(ctx, client.ObjectKey{Namespace: "default", Name: "key"}, &v1alpha1.Key{})
+		},
+	}
+	for _, read := range reads {
+		if err := read(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if expected := []string{"get repo", "list", "get key"}; !reflect.DeepEqual(cached.reads, expected) {
+		t.Errorf("expected the cache to serve %v, got %v", expected, cached.reads)
+	}
+	if expected := []string{"get orgname", "list"}; !reflect.DeepEqual(api.reads, expected) {
+		t.Errorf("expected the API server to serve %v, got %v", expected, api.reads)
+	}
+}
+
+func TestNamespacedClient(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"apiVersion":"github.go.hein.dev/v1alpha1","kind":"OrganizationPolicy","metadata":{"name":"orgname"},"spec":{"organization":"orgname"}}`))
+	}))
+	defer server.Close()
+
+	cached := &recordingCache{}
+	c, err := NamespacedClient(cached, &rest.Config{Host: server.URL}, client.Options{Scheme: testScheme(t), Mapper: testMapper()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "repo"}, &v1alpha1.Repository{}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"get repo"}; !reflect.DeepEqual(cached.reads, expected) {
+		t.Errorf("expected the namespaced read from the cache, got %v", cached.reads)
+	}
+
+	var policy v1alpha1.OrganizationPolicy
+	if err := c.Get(ctx, client.ObjectKey{Name: "orgname"}, &policy); err != nil {
+		t.Fatal(err)
+	}
+	if policy.Spec.Organization != "orgname" {
+		t.Errorf("expected the policy read from the API server, got %+v", policy)
+	}
+	if expected := []string{"GET /apis/github.go.hein.dev/v1alpha1/organizationpolicies/orgname"}; !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected %v, got %v", expected, requests)
+	}
+}
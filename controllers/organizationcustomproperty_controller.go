@@ -0,0 +1,186 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1alpha1 "go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+)
+
+var (
+	customPropertyFinalizerName = "organizationcustomproperty.finalizers.github.go.hein.dev"
+)
+
+// OrganizationCustomPropertyReconciler reconciles a OrganizationCustomProperty object
+type OrganizationCustomPropertyReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	GitClient    git.Client
+	ActualDelete bool
+
+	// Recorder records an event for every change made on GitHub
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=github.go.hein.dev,resources=organizationcustomproperties,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=github.go.hein.dev,resources=organizationcustomproperties/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=github.go.hein.dev,resources=organizationpolicies,verbs=get;list;watch
+
+// Reconcile is responsible for reconciling the request
+func (r *OrganizationCustomPropertyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	var requeueAfter = 2 * time.Second
+	ctx, plan := git.WithPlan(context.Background())
+	log := r.Log.WithValues("organizationcustomproperty", req.NamespacedName)
+
+	var property v1alpha1.OrganizationCustomProperty
+	if err := r.Client.Get(ctx, req.NamespacedName, &property); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// the changes skipped by the dry-run client are stored whichever way the reconcile ends
+	defer func() {
+		if err := r.updateCustomPropertyPlan(ctx, &property, plan); err != nil {
+			log.Error(err, "unable to update planned changes")
+		}
+	}()
+
+	if !property.ObjectMeta.DeletionTimestamp.IsZero() &&
+		containsString(property.GetFinalizers(), customPropertyFinalizerName) {
+		log.Info("handle deletion", "name", property.Name)
+		if err := r.handleDeletion(ctx, &property); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if property.ObjectMeta.DeletionTimestamp.IsZero() &&
+		!containsString(property.GetFinalizers(), customPropertyFinalizerName) {
+		log.Info("adding finalizer", "name", property.Name)
+		if err := r.addFinalizer(ctx, &property); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	org, name := property.Spec.Organization, property.GitHubPropertyName()
+	log = log.WithValues("organization", org, "property", name)
+
+	// organizations no policy covers are left alone on GitHub
+	allowed, policyErr := r.checkPolicy(ctx, &property, org)
+	if policyErr != nil {
+		return ctrl.Result{}, policyErr
+	}
+	if !allowed {
+		log.Info("custom property not allowed by organization policies")
+		return ctrl.Result{}, nil
+	}
+
+	// remove the definition left behind when the organization or name changed, it
+	// holds the values of every repository so it is kept without ActualDelete
+	if previous := property.Status; previous.GitHubOrganization != "" &&
+		(previous.GitHubOrganization != org || previous.PropertyName != name) {
+		if r.ActualDelete {
+			log.Info("removing previous custom property", "previousOrganization", previous.GitHubOrganization, "previousProperty", previous.PropertyName)
+			if err := r.GitClient.DeleteCustomProperty(ctx, previous.GitHubOrganization, previous.PropertyName); err != nil {
+				return ctrl.Result{RequeueAfter: requeueAfter}, err
+			}
+			r.Recorder.Eventf(&property, corev1.EventTypeNormal, "Deleted", "deleted custom property %s from %s", previous.PropertyName, previous.GitHubOrganization)
+		} else {
+			log.Info("actual delete false, keeping previous custom property", "previousOrganization", previous.GitHubOrganization, "previousProperty", previous.PropertyName)
+			r.Recorder.Eventf(&property, corev1.EventTypeNormal, "DeleteSkipped", "kept custom property %s in %s, --actual-delete is off", previous.PropertyName, previous.GitHubOrganization)
+		}
+	}
+
+	desired := desiredCustomProperty(&property)
+	current, err := r.GitClient.GetCustomProperty(ctx, org, name)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
+
+	if current == nil || !customPropertyMatches(current, desired) {
+		r.updateCustomPropertyStatus(ctx, &property, v1alpha1.UpdatingStatus)
+		log.Info("updating custom property")
+		if err := r.GitClient.CreateOrUpdateCustomProperty(ctx, org, name, desired); err != nil {
+			return ctrl.Result{RequeueAfter: requeueAfter}, err
+		}
+		r.Recorder.Eventf(&property, corev1.EventTypeNormal, "Updated", "updated custom property %s in %s", name, org)
+	}
+
+	// the dry-run client only planned the changes, the previous definition must
+	// stay in the status so it is still cleaned up once the changes are applied
+	if changes := plan.Changes(); len(changes) > 0 {
+		log.Info("planned custom property changes", "count", len(changes))
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.updateCustomPropertyStatusDetails(ctx, &property, org, name); err != nil {
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager configures the controller
+func (r *OrganizationCustomPropertyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("organizationcustomproperty-controller")
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.OrganizationCustomProperty{}).
+		Watches(&source.Kind{Type: &v1alpha1.OrganizationPolicy{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.policyToCustomProperties),
+		}).
+		Complete(r)
+}
+
+// policyToCustomProperties queues every OrganizationCustomProperty, a policy
+// can allow or deny the organization of any of them
+func (r *OrganizationCustomPropertyReconciler) policyToCustomProperties(obj handler.MapObject) []reconcile.Request {
+	var list v1alpha1.OrganizationCustomPropertyList
+	if err := r.Client.List(context.Background(), &list); err != nil {
+		r.Log.Error(err, "unable to list custom properties", "organizationPolicy", obj.Meta.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, property := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: property.Name},
+		})
+	}
+	return requests
+}
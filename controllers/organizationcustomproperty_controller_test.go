@@ -0,0 +1,116 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+)
+
+var _ = Describe("Run OrganizationCustomProperty Controller", func() {
+	const timeout = time.Second * 10
+	const interval = time.Millisecond * 500
+
+	Context("Run a new OrganizationCustomProperty", func() {
+		It("Should define and delete the property", func() {
+			key := types.NamespacedName{Name: "test-property"}
+			property := &v1alpha1.OrganizationCustomProperty{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: v1alpha1.OrganizationCustomPropertySpec{
+					Organization: "awsctrl",
+					ValueType:    v1alpha1.StringCustomProperty,
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), property)).Should(Succeed())
+
+			By("Describing OrganizationCustomProperty Finalizers")
+			Eventually(func() bool {
+				p := &v1alpha1.OrganizationCustomProperty{}
+				k8sClient.Get(context.Background(), key, p)
+				return len(p.GetFinalizers()) == 1
+			}, timeout, interval).Should(BeTrue())
+
+			By("Describing Synced Status")
+			Eventually(func() bool {
+				p := &v1alpha1.OrganizationCustomProperty{}
+				k8sClient.Get(context.Background(), key, p)
+				return p.Status.Status == v1alpha1.SyncedStatus && p.Status.PropertyName == key.Name
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() bool {
+				return hasEvent(metav1.NamespaceDefault, key.Name, "Updated")
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(k8sClient.Delete(context.Background(), property)).Should(Succeed())
+
+			By("Describing deletion state")
+			Eventually(func() bool {
+				return isGone(key, &v1alpha1.OrganizationCustomProperty{})
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() bool {
+				return hasEvent(metav1.NamespaceDefault, key.Name, "Deleted")
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+
+	Context("Run an OrganizationCustomProperty no policy covers", func() {
+		It("Should be Denied", func() {
+			policy := &v1alpha1.OrganizationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-property-policy"},
+				Spec: v1alpha1.OrganizationPolicySpec{
+					Organization: "policyorg",
+					Namespaces:   []string{"default"},
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), policy)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), policy)).Should(Succeed())
+			}()
+
+			key := types.NamespacedName{Name: "test-denied-property"}
+			property := &v1alpha1.OrganizationCustomProperty{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: v1alpha1.OrganizationCustomPropertySpec{
+					Organization: "awsctrl",
+					ValueType:    v1alpha1.StringCustomProperty,
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), property)).Should(Succeed())
+
+			By("Describing Denied Status")
+			Eventually(func() bool {
+				p := &v1alpha1.OrganizationCustomProperty{}
+				k8sClient.Get(context.Background(), key, p)
+				return p.Status.Status == v1alpha1.DeniedStatus && p.Status.Message != ""
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() bool {
+				return hasEvent(metav1.NamespaceDefault, key.Name, policyViolationReason)
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(k8sClient.Delete(context.Background(), property)).Should(Succeed())
+			Eventually(func() bool {
+				return isGone(key, &v1alpha1.OrganizationCustomProperty{})
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+})
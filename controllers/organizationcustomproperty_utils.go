@@ -0,0 +1,173 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+	"go.hein.dev/github-controller/policy"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+func (r *OrganizationCustomPropertyReconciler) addFinalizer(ctx context.Context, property *v1alpha1.OrganizationCustomProperty) error {
+	property.ObjectMeta.Finalizers = append(property.ObjectMeta.Finalizers, customPropertyFinalizerName)
+	if err := r.Client.Update(ctx, property); err != nil {
+		return err
+	}
+
+	return r.updateCustomPropertyStatus(ctx, property, v1alpha1.CreatingStatus)
+}
+
+func (r *OrganizationCustomPropertyReconciler) handleDeletion(ctx context.Context, property *v1alpha1.OrganizationCustomProperty) error {
+	org := property.Status.GitHubOrganization
+	name := property.Status.PropertyName
+
+	deleteProperty := org != "" && r.ActualDelete
+	if deleteProperty {
+		released, err := releasedByPolicy(ctx, r.Client, r.Recorder, property, "", org)
+		if err != nil {
+			return err
+		}
+		deleteProperty = !released
+	}
+
+	if deleteProperty {
+		r.Log.Info("actual delete true", "deleting", fmt.Sprintf("%s/%s", org, name))
+		if err := r.GitClient.DeleteCustomProperty(ctx, org, name); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(property, corev1.EventTypeNormal, "Deleted", "deleted custom property %s from %s", name, org)
+	}
+
+	property.ObjectMeta.Finalizers = removeString(property.ObjectMeta.Finalizers, customPropertyFinalizerName)
+	if err := r.Client.Update(context.Background(), property); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *OrganizationCustomPropertyReconciler) updateCustomPropertyStatusDetails(ctx context.Context, property *v1alpha1.OrganizationCustomProperty, org, name string) error {
+	nsn := types.NamespacedName{Name: property.Name}
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var property v1alpha1.OrganizationCustomProperty
+		if err := r.Client.Get(ctx, nsn, &property); err != nil {
+			return err
+		}
+
+		propertyCopy := property.DeepCopy()
+		propertyCopy.Status = v1alpha1.OrganizationCustomPropertyStatus{
+			Status:             v1alpha1.SyncedStatus,
+			GitHubOrganization: org,
+			PropertyName:       name,
+		}
+
+		return r.Client.Status().Update(ctx, propertyCopy)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkPolicy reports whether an organization policy covers the organization
+// of the property, denied properties get the Denied status with the reason
+func (r *OrganizationCustomPropertyReconciler) checkPolicy(ctx context.Context, property *v1alpha1.OrganizationCustomProperty, org string) (bool, error) {
+	reason, err := policyViolation(policy.CheckOrganization(ctx, r.Client, "", org))
+	if err != nil || reason == "" {
+		return err == nil, err
+	}
+
+	if property.Status.Status != v1alpha1.DeniedStatus || property.Status.Message != reason {
+		r.Recorder.Event(property, corev1.EventTypeWarning, policyViolationReason, reason)
+	}
+
+	nsn := types.NamespacedName{Name: property.Name}
+	return false, retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var property v1alpha1.OrganizationCustomProperty
+		if err := r.Client.Get(ctx, nsn, &property); err != nil {
+			return err
+		}
+
+		if property.Status.Status == v1alpha1.DeniedStatus && property.Status.Message == reason {
+			return nil // no need to update
+		}
+
+		propertyCopy := property.DeepCopy()
+		propertyCopy.Status.Status = v1alpha1.DeniedStatus
+		propertyCopy.Status.Message = reason
+
+		return r.Client.Status().Update(ctx, propertyCopy)
+	})
+}
+
+func (r *OrganizationCustomPropertyReconciler) updateCustomPropertyStatus(ctx context.Context, property *v1alpha1.OrganizationCustomProperty, status v1alpha1.StatusReason) error {
+	nsn := types.NamespacedName{Name: property.Name}
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var property v1alpha1.OrganizationCustomProperty
+		if err := r.Client.Get(ctx, nsn, &property); err != nil {
+			return err
+		}
+
+		if property.Status.Status == status {
+			return nil // no need to update
+		}
+
+		propertyCopy := property.DeepCopy()
+		propertyCopy.Status.Status = status
+		propertyCopy.Status.Message = ""
+
+		return r.Client.Status().Update(ctx, propertyCopy)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// desiredCustomProperty converts the spec to the custom property schema API
+func desiredCustomProperty(property *v1alpha1.OrganizationCustomProperty) *git.CustomProperty {
+	desired := &git.CustomProperty{
+		ValueType: string(property.Spec.ValueType),
+		Required:  property.Spec.Required,
+	}
+	if property.Spec.DefaultValue != "" {
+		desired.DefaultValue = &property.Spec.DefaultValue
+	}
+	if property.Spec.Description != "" {
+		desired.Description = &property.Spec.Description
+	}
+	switch property.Spec.ValueType {
+	case v1alpha1.SingleSelectCustomProperty, v1alpha1.MultiSelectCustomProperty:
+		desired.AllowedValues = property.Spec.AllowedValues
+	}
+	return desired
+}
+
+// customPropertyMatches reports whether the observed schema equals the desired one
+func customPropertyMatches(current, desired *git.CustomProperty) bool {
+	return current.ValueType == desired.ValueType &&
+		current.Required == desired.Required &&
+		stringValue(current.DefaultValue) == stringValue(desired.DefaultValue) &&
+		stringValue(current.Description) == stringValue(desired.Description) &&
+		strings.Join(current.AllowedValues, ",") == strings.Join(desired.AllowedValues, ",")
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+)
+
+const (
+	pausedReason        = "Paused"
+	resumedReason       = "Resumed"
+	deletePausedReason  = "DeletePaused"
+	deletePausedMessage = "deletion waits until the " + v1alpha1.PausedAnnotation + " annotation is removed"
+)
+
+// setPausedCondition sets the Paused condition from the annotation, objects
+// that were never paused don't get the condition, it reports whether the
+// condition changed
+func setPausedCondition(conditions *[]v1alpha1.Condition, paused bool) bool {
+	current := v1alpha1.FindCondition(*conditions, v1alpha1.PausedCondition)
+	switch {
+	case paused && (current == nil || current.Status != corev1.ConditionTrue):
+		v1alpha1.SetCondition(conditions, v1alpha1.Condition{
+			Type:    v1alpha1.PausedCondition,
+			Status:  corev1.ConditionTrue,
+			Reason:  pausedReason,
+			Message: "GitHub is not changed while the " + v1alpha1.PausedAnnotation + " annotation is true",
+		})
+		return true
+	case !paused && current != nil && current.Status != corev1.ConditionFalse:
+		v1alpha1.SetCondition(conditions, v1alpha1.Condition{
+			Type:    v1alpha1.PausedCondition,
+			Status:  corev1.ConditionFalse,
+			Reason:  resumedReason,
+			Message: "reconciliation resumed",
+		})
+		return true
+	}
+	return false
+}
+
+// handlePaused reports whether the repository is paused and reflects it in the
+// conditions. A paused repository that is deleted keeps its finalizer until it
+// is resumed, with ReleasePausedOnDelete it is released without deleting it on
+// GitHub instead.
+func (r *RepositoryReconciler) handlePaused(ctx context.Context, repository *v1alpha1.Repository) (bool, error) {
+	paused := v1alpha1.IsPaused(repository)
+	if paused && !repository.ObjectMeta.DeletionTimestamp.IsZero() &&
+		containsString(repository.GetFinalizers(), repoFinalizerName) {
+		if !r.ReleasePausedOnDelete {
+			r.Recorder.Event(repository, corev1.EventTypeNormal, deletePausedReason, deletePausedMessage)
+			return paused, nil
+		}
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "DeleteSkipped", "released repository %s/%s while paused", repository.Spec.Organization, repository.GitHubName())
+		repository.ObjectMeta.Finalizers = removeString(repository.ObjectMeta.Finalizers, repoFinalizerName)
+		return paused, r.Client.Update(ctx, repository)
+	}
+
+	if setPausedCondition(&repository.Status.Conditions, paused) {
+		if err := r.updateRepositoryConditions(ctx, repository); err != nil {
+			return paused, err
+		}
+		if paused {
+			r.Recorder.Event(repository, corev1.EventTypeNormal, pausedReason, "reconciliation paused")
+		} else {
+			r.Recorder.Event(repository, corev1.EventTypeNormal, resumedReason, "reconciliation resumed")
+		}
+	}
+	return paused, nil
+}
+
+// handlePaused reports whether the key is paused and reflects it in the
+// conditions. A paused key that is deleted keeps its finalizer until it is
+// resumed, with ReleasePausedOnDelete it is released without deleting it on
+// GitHub instead.
+func (r *KeyReconciler) handlePaused(ctx context.Context, key *v1alpha1.Key) (bool, error) {
+	paused := v1alpha1.IsPaused(key)
+	if paused && !key.ObjectMeta.DeletionTimestamp.IsZero() &&
+		containsString(key.GetFinalizers(), keyFinalizerName) {
+		if !r.ReleasePausedOnDelete {
+			r.Recorder.Event(key, corev1.EventTypeNormal, deletePausedReason, deletePausedMessage)
+			return paused, nil
+		}
+		r.Recorder.Eventf(key, corev1.EventTypeNormal, "DeleteSkipped", "released key %d while paused", key.Status.GitHubKeyID)
+		key.ObjectMeta.Finalizers = removeString(key.ObjectMeta.Finalizers, keyFinalizerName)
+		return paused, r.Client.Update(ctx, key)
+	}
+
+	if setPausedCondition(&key.Status.Conditions, paused) {
+		if err := r.updateKeyConditions(ctx, key); err != nil {
+			return paused, err
+		}
+		if paused {
+			r.Recorder.Event(key, corev1.EventTypeNormal, pausedReason, "reconciliation paused")
+		} else {
+			r.Recorder.Event(key, corev1.EventTypeNormal, resumedReason, "reconciliation resumed")
+		}
+	}
+	return paused, nil
+}
+
+// updateKeyConditions stores the conditions of key
+func (r *KeyReconciler) updateKeyConditions(ctx context.Context, key *v1alpha1.Key) error {
+	nsn := types.NamespacedName{Namespace: key.Namespace, Name: key.Name}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var current v1alpha1.Key
+		if err := r.Client.Get(ctx, nsn, &current); err != nil {
+			return err
+		}
+
+		keyCopy := current.DeepCopy()
+		keyCopy.Status.Conditions = key.Status.Conditions
+
+		return r.Client.Status().Update(ctx, keyCopy)
+	})
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// reconcileVariables creates and updates the Actions variables in the spec and
+// removes the ones that were managed before but are no longer in the spec, the
+// ones --actual-delete keeps stay managed until they are removed
+func (r *RepositoryReconciler) reconcileVariables(ctx context.Context, repository *v1alpha1.Repository) error {
+	if len(repository.Spec.Variables) == 0 && len(repository.Status.ManagedVariables) == 0 {
+		return nil
+	}
+
+	org, name := repository.Spec.Organization, repository.GitHubName()
+	log := r.Log.WithValues("repository", org+"/"+name)
+
+	existing, err := r.GitClient.ListVariables(ctx, org, name)
+	if err != nil {
+		return err
+	}
+
+	// variable names are case insensitive and returned upper cased
+	observed := map[string]*git.Variable{}
+	for _, variable := range existing {
+		observed[strings.ToUpper(variable.Name)] = variable
+	}
+
+	desired := map[string]bool{}
+	managed := []string{}
+	for _, variable := range repository.Spec.Variables {
+		desired[strings.ToUpper(variable.Name)] = true
+		managed = append(managed, variable.Name)
+
+		current, ok := observed[strings.ToUpper(variable.Name)]
+		switch {
+		case !ok:
+			log.Info("creating variable", "variable", variable.Name)
+			if err = r.GitClient.CreateVariable(ctx, org, name, &git.Variable{Name: variable.Name, Value: variable.Value}); err == nil {
+				r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Created", "created variable %s", variable.Name)
+				driftCorrections.WithLabelValues("Repository", "variables").Inc()
+			}
+		case current.Value != variable.Value:
+			log.Info("updating variable", "variable", variable.Name)
+			if err = r.GitClient.UpdateVariable(ctx, org, name, &git.Variable{Name: variable.Name, Value: variable.Value}); err == nil {
+				r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Updated", "updated variable %s", variable.Name)
+				driftCorrections.WithLabelValues("Repository", "variables").Inc()
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, variable := range repository.Status.ManagedVariables {
+		if desired[strings.ToUpper(variable)] {
+			continue
+		}
+		if _, ok := observed[strings.ToUpper(variable)]; !ok {
+			continue
+		}
+		if !r.ActualDelete {
+			r.Recorder.Eventf(repository, corev1.EventTypeNormal, "DeleteSkipped", "kept variable %s, --actual-delete is off", variable)
+			managed = append(managed, variable)
+			continue
+		}
+		log.Info("deleting variable", "variable", variable)
+		if err := r.GitClient.DeleteVariable(ctx, org, name, variable); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Deleted", "deleted variable %s", variable)
+		driftCorrections.WithLabelValues("Repository", "variables").Inc()
+	}
+
+	repository.Status.ManagedVariables = managed
+	return nil
+}
+
+// reconcileEnvironments creates and updates the environments in the spec and
+// removes the ones that were managed before but are no longer in the spec, the
+// ones --actual-delete keeps stay managed until they are removed
+func (r *RepositoryReconciler) reconcileEnvironments(ctx context.Context, repository *v1alpha1.Repository) error {
+	if len(repository.Spec.Environments) == 0 && len(repository.Status.ManagedEnvironments) == 0 {
+		return nil
+	}
+
+	org, name := repository.Spec.Organization, repository.GitHubName()
+	log := r.Log.WithValues("repository", org+"/"+name)
+
+	existing, err := r.GitClient.ListEnvironments(ctx, org, name)
+	if err != nil {
+		return err
+	}
+
+	// environment names are case insensitive
+	observed := map[string]*git.Environment{}
+	for _, env := range existing {
+		observed[strings.ToLower(env.Name)] = env
+	}
+
+	desired := map[string]bool{}
+	managed := []string{}
+	for i := range repository.Spec.Environments {
+		env := &repository.Spec.Environments[i]
+		desired[strings.ToLower(env.Name)] = true
+		managed = append(managed, env.Name)
+
+		if current, ok := observed[strings.ToLower(env.Name)]; !ok || !environmentMatches(current, env) {
+			log.Info("updating environment", "environment", env.Name)
+			if err := r.GitClient.CreateOrUpdateEnvironment(ctx, org, name, env); err != nil {
+				return err
+			}
+			r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Updated", "updated environment %s", env.Name)
+			driftCorrections.WithLabelValues("Repository", "environments").Inc()
+		}
+
+		if policy := env.DeploymentBranchPolicy; policy != nil && !policy.ProtectedBranches {
+			if err := r.reconcileBranchPolicies(ctx, repository, env); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, env := range repository.Status.ManagedEnvironments {
+		if desired[strings.ToLower(env)] {
+			continue
+		}
+		if _, ok := observed[strings.ToLower(env)]; !ok {
+			continue
+		}
+		if !r.ActualDelete {
+			r.Recorder.Eventf(repository, corev1.EventTypeNormal, "DeleteSkipped", "kept environment %s, --actual-delete is off", env)
+			managed = append(managed, env)
+			continue
+		}
+		log.Info("deleting environment", "environment", env)
+		if err := r.GitClient.DeleteEnvironment(ctx, org, name, env); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Deleted", "deleted environment %s", env)
+		driftCorrections.WithLabelValues("Repository", "environments").Inc()
+	}
+
+	repository.Status.ManagedEnvironments = managed
+	return nil
+}
+
+// reconcileBranchPolicies makes the custom deployment branch policies of the
+// environment match the branches in the spec
+func (r *RepositoryReconciler) reconcileBranchPolicies(ctx context.Context, repository *v1alpha1.Repository, env *v1alpha1.RepositoryEnvironment) error {
+	org, name := repository.Spec.Organization, repository.GitHubName()
+	log := r.Log.WithValues("repository", org+"/"+name, "environment", env.Name)
+
+	existing, err := r.GitClient.ListBranchPolicies(ctx, org, name, env.Name)
+	if err != nil {
+		return err
+	}
+
+	observed := map[string]bool{}
+	for _, policy := range existing {
+		observed[policy.Name] = true
+	}
+
+	desired := map[string]bool{}
+	for _, branch := range env.DeploymentBranchPolicy.Branches {
+		desired[branch] = true
+		if observed[branch] {
+			continue
+		}
+		log.Info("creating deployment branch policy", "branch", branch)
+		if err := r.GitClient.CreateBranchPolicy(ctx, org, name, env.Name, branch); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Created", "created deployment branch policy %s for environment %s", branch, env.Name)
+		driftCorrections.WithLabelValues("Repository", "deploymentBranchPolicies").Inc()
+	}
+
+	for _, policy := range existing {
+		if desired[policy.Name] {
+			continue
+		}
+		if !r.ActualDelete {
+			r.Recorder.Eventf(repository, corev1.EventTypeNormal, "DeleteSkipped", "kept deployment branch policy %s of environment %s, --actual-delete is off", policy.Name, env.Name)
+			continue
+		}
+		log.Info("deleting deployment branch policy", "branch", policy.Name)
+		if err := r.GitClient.DeleteBranchPolicy(ctx, org, name, env.Name, policy.ID); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Deleted", "deleted deployment branch policy %s from environment %s", policy.Name, env.Name)
+		driftCorrections.WithLabelValues("Repository", "deploymentBranchPolicies").Inc()
+	}
+	return nil
+}
+
+// environmentMatches reports whether the observed environment has the
+// protection rules of the desired one
+func environmentMatches(current *git.Environment, desired *v1alpha1.RepositoryEnvironment) bool {
+	var waitTimer int
+	observedReviewers := []string{}
+	for _, rule := range current.ProtectionRules {
+		switch rule.Type {
+		case "wait_timer":
+			waitTimer = rule.WaitTimer
+		case "required_reviewers":
+			for _, reviewer := range rule.Reviewers {
+				name := reviewer.Reviewer.Login
+				if reviewer.Type == string(v1alpha1.TeamReviewer) {
+					name = reviewer.Reviewer.Slug
+				}
+				observedReviewers = append(observedReviewers, strings.ToLower(reviewer.Type+"/"+name))
+			}
+		}
+	}
+	if waitTimer != desired.WaitTimer {
+		return false
+	}
+
+	desiredReviewers := []string{}
+	for _, reviewer := range desired.Reviewers {
+		desiredReviewers = append(desiredReviewers, strings.ToLower(string(reviewer.Type)+"/"+reviewer.Name))
+	}
+	sort.Strings(observedReviewers)
+	sort.Strings(desiredReviewers)
+	if strings.Join(observedReviewers, ",") != strings.Join(desiredReviewers, ",") {
+		return false
+	}
+
+	policy := desired.DeploymentBranchPolicy
+	if policy == nil || current.DeploymentBranchPolicy == nil {
+		return policy == nil && current.DeploymentBranchPolicy == nil
+	}
+	return current.DeploymentBranchPolicy.ProtectedBranches == policy.ProtectedBranches &&
+		current.DeploymentBranchPolicy.CustomBranchPolicies == !policy.ProtectedBranches
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// reconcileAutolinks makes the autolink references of the repository match the
+// spec, autolinks can't be updated so drifted ones are deleted and recreated.
+// Nothing is deleted without ActualDelete, kept autolinks stay managed.
+func (r *RepositoryReconciler) reconcileAutolinks(ctx context.Context, repository *v1alpha1.Repository) error {
+	if len(repository.Spec.Autolinks) == 0 && len(repository.Status.ManagedAutolinks) == 0 {
+		return nil
+	}
+
+	org, name := repository.Spec.Organization, repository.GitHubName()
+	log := r.Log.WithValues("repository", org+"/"+name)
+
+	existing, err := r.GitClient.ListAutolinks(ctx, org, name)
+	if err != nil {
+		return err
+	}
+
+	// key prefixes are unique case insensitively
+	desired := map[string]*git.Autolink{}
+	managed := []string{}
+	for _, autolink := range repository.Spec.Autolinks {
+		desired[strings.ToLower(autolink.KeyPrefix)] = desiredAutolink(&autolink)
+		managed = append(managed, autolink.KeyPrefix)
+	}
+
+	previouslyManaged := map[string]bool{}
+	for _, prefix := range repository.Status.ManagedAutolinks {
+		previouslyManaged[strings.ToLower(prefix)] = true
+	}
+
+	inSync := map[string]bool{}
+	for _, autolink := range existing {
+		key := strings.ToLower(autolink.KeyPrefix)
+		want, ok := desired[key]
+		if ok && !inSync[key] && autolinkMatches(autolink, want) {
+			inSync[key] = true
+			continue
+		}
+		if !ok && len(repository.Spec.Autolinks) == 0 && !previouslyManaged[key] {
+			continue
+		}
+		if !r.ActualDelete {
+			r.Recorder.Eventf(repository, corev1.EventTypeNormal, "DeleteSkipped", "kept autolink %s, --actual-delete is off", autolink.KeyPrefix)
+			switch {
+			case ok:
+				// the prefix is taken, so the desired autolink can't be created next to it
+				inSync[key] = true
+			case previouslyManaged[key]:
+				managed = append(managed, autolink.KeyPrefix)
+			}
+			continue
+		}
+
+		log.Info("deleting autolink", "keyPrefix", autolink.KeyPrefix)
+		if err := r.GitClient.DeleteAutolink(ctx, org, name, autolink.ID); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Deleted", "deleted autolink %s", autolink.KeyPrefix)
+		driftCorrections.WithLabelValues("Repository", "autolinks").Inc()
+	}
+
+	for _, autolink := range repository.Spec.Autolinks {
+		if inSync[strings.ToLower(autolink.KeyPrefix)] {
+			continue
+		}
+		log.Info("creating autolink", "keyPrefix", autolink.KeyPrefix)
+		if err := r.GitClient.CreateAutolink(ctx, org, name, desired[strings.ToLower(autolink.KeyPrefix)]); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Created", "created autolink %s", autolink.KeyPrefix)
+		driftCorrections.WithLabelValues("Repository", "autolinks").Inc()
+	}
+
+	repository.Status.ManagedAutolinks = managed
+	return nil
+}
+
+// autolinkMatches reports whether the observed autolink has the settings of the desired one
+func autolinkMatches(current, desired *git.Autolink) bool {
+	return current.KeyPrefix == desired.KeyPrefix &&
+		current.URLTemplate == desired.URLTemplate &&
+		current.IsAlphanumeric == desired.IsAlphanumeric
+}
+
+func desiredAutolink(autolink *v1alpha1.RepositoryAutolink) *git.Autolink {
+	alphanumeric := true
+	if autolink.Alphanumeric != nil {
+		alphanumeric = *autolink.Alphanumeric
+	}
+	return &git.Autolink{
+		KeyPrefix:      autolink.KeyPrefix,
+		URLTemplate:    autolink.URLTemplate,
+		IsAlphanumeric: alphanumeric,
+	}
+}
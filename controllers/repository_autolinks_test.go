@@ -0,0 +1,145 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/google/go-github/v28/github"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+)
+
+func TestDesiredAutolink(t *testing.T) {
+	tests := []struct {
+		name     string
+		autolink v1alpha1.RepositoryAutolink
+		expected *git.Autolink
+	}{
+		{
+			"alphanumeric by default",
+			v1alpha1.RepositoryAutolink{KeyPrefix: "JIRA-", URLTemplate: "https://jira.example.com/browse/JIRA-<num>"},
+			&git.Autolink{KeyPrefix: "JIRA-", URLTemplate: "https://jira.example.com/browse/JIRA-<num>", IsAlphanumeric: true},
+		},
+		{
+			"numeric",
+			v1alpha1.RepositoryAutolink{KeyPrefix: "TICKET-", URLTemplate: "https://example.com/<num>", Alphanumeric: github.Bool(false)},
+			&git.Autolink{KeyPrefix: "TICKET-", URLTemplate: "https://example.com/<num>", IsAlphanumeric: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := desiredAutolink(&tt.autolink); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestAutolinkMatches(t *testing.T) {
+	desired := &git.Autolink{KeyPrefix: "JIRA-", URLTemplate: "https://jira.example.com/browse/JIRA-<num>", IsAlphanumeric: true}
+
+	tests := []struct {
+		name     string
+		current  *git.Autolink
+		expected bool
+	}{
+		{"same", &git.Autolink{ID: 1, KeyPrefix: "JIRA-", URLTemplate: "https://jira.example.com/browse/JIRA-<num>", IsAlphanumeric: true}, true},
+		{"url template", &git.Autolink{ID: 1, KeyPrefix: "JIRA-", URLTemplate: "https://example.com/<num>", IsAlphanumeric: true}, false},
+		{"numeric", &git.Autolink{ID: 1, KeyPrefix: "JIRA-", URLTemplate: "https://jira.example.com/browse/JIRA-<num>"}, false},
+		{"prefix case", &git.Autolink{ID: 1, KeyPrefix: "jira-", URLTemplate: "https://jira.example.com/browse/JIRA-<num>", IsAlphanumeric: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := autolinkMatches(tt.current, desired); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestReconcileAutolinks(t *testing.T) {
+	tests := []struct {
+		name            string
+		actualDelete    bool
+		expectedURLs    []string
+		expectedManaged []string
+	}{
+		{
+			"keeps drifted and other autolinks",
+			false,
+			[]string{"https://example.com/<num>", "https://old.example.com/<num>", "https://other.example.com/<num>"},
+			[]string{"JIRA-", "OLD-"},
+		},
+		{
+			"recreates drifted and deletes the others",
+			true,
+			[]string{"https://jira.example.com/browse/JIRA-<num>"},
+			[]string{"JIRA-"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			gitClient := git.TestClient()
+			for _, autolink := range []*git.Autolink{
+				{KeyPrefix: "JIRA-", URLTemplate: "https://example.com/<num>", IsAlphanumeric: true},
+				{KeyPrefix: "OLD-", URLTemplate: "https://old.example.com/<num>", IsAlphanumeric: true},
+				{KeyPrefix: "OTHER-", URLTemplate: "https://other.example.com/<num>", IsAlphanumeric: true},
+			} {
+				if err := gitClient.CreateAutolink(ctx, "org", "repo", autolink); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			repository := &v1alpha1.Repository{
+				Spec: v1alpha1.RepositorySpec{
+					Organization: "org",
+					Name:         "repo",
+					Autolinks:    []v1alpha1.RepositoryAutolink{{KeyPrefix: "JIRA-", URLTemplate: "https://jira.example.com/browse/JIRA-<num>"}},
+				},
+				Status: v1alpha1.RepositoryStatus{ManagedAutolinks: []string{"JIRA-", "OLD-"}},
+			}
+			if err := testRepositoryReconciler(gitClient, tt.actualDelete).reconcileAutolinks(ctx, repository); err != nil {
+				t.Fatal(err)
+			}
+
+			autolinks, err := gitClient.ListAutolinks(ctx, "org", "repo")
+			if err != nil {
+				t.Fatal(err)
+			}
+			urls := []string{}
+			for _, autolink := range autolinks {
+				urls = append(urls, autolink.URLTemplate)
+			}
+			sort.Strings(urls)
+			if !reflect.DeepEqual(urls, tt.expectedURLs) {
+				t.Errorf("expected autolinks %v, got %v", tt.expectedURLs, urls)
+			}
+			if !reflect.DeepEqual(repository.Status.ManagedAutolinks, tt.expectedManaged) {
+				t.Errorf("expected managed autolinks %v, got %v", tt.expectedManaged, repository.Status.ManagedAutolinks)
+			}
+		})
+	}
+}
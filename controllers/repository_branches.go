@@ -0,0 +1,92 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/google/go-github/v28/github"
+	"go.hein.dev/github-controller/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// reconcileDefaultBranch renames or switches the default branch of ghrepo to
+// the one in the spec, ghrepo is updated with the resulting default branch.
+// Until the first sync recorded a default branch the initial branch is renamed
+// after spec.init.defaultBranch, so a failed rename is retried.
+func (r *RepositoryReconciler) reconcileDefaultBranch(ctx context.Context, repository *v1alpha1.Repository, ghrepo *github.Repository) error {
+	desired, strategy := repository.Spec.DefaultBranch, repository.Spec.DefaultBranchStrategy
+	if desired == "" && repository.Status.DefaultBranch == "" && initializedByController(repository) {
+		desired, strategy = repository.Spec.Init.DefaultBranch, v1alpha1.RenameDefaultBranch
+	}
+	current := ghrepo.GetDefaultBranch()
+	if desired == "" || desired == current {
+		return nil
+	}
+
+	org, name := repository.Spec.Organization, repository.GitHubName()
+	log := r.Log.WithValues("repository", org+"/"+name)
+
+	// there is no branch to rename or switch from before the first commit
+	initialized, err := r.GitClient.RepoInitialized(ctx, org, name)
+	if err != nil {
+		return err
+	}
+	if !initialized {
+		log.Info("skipping default branch until the repository has commits", "defaultBranch", desired)
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Skipped", "default branch %s is applied once the repository has commits", desired)
+		return nil
+	}
+
+	// a branch can't be renamed onto an existing one, make that one the default instead
+	if strategy != v1alpha1.SwitchDefaultBranch {
+		exists, err := r.GitClient.BranchExists(ctx, org, name, desired)
+		if err != nil {
+			return err
+		}
+		if exists {
+			log.Info("default branch already exists, switching instead of renaming", "defaultBranch", desired)
+			strategy = v1alpha1.SwitchDefaultBranch
+		}
+	}
+
+	switch strategy {
+	case v1alpha1.SwitchDefaultBranch:
+		log.Info("switching default branch", "from", current, "to", desired)
+		if _, err := r.GitClient.EditRepo(ctx, org, name, &github.Repository{DefaultBranch: &desired}); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Updated", "switched default branch from %s to %s", current, desired)
+		driftCorrections.WithLabelValues("Repository", "defaultBranch").Inc()
+	default:
+		log.Info("renaming default branch", "from", current, "to", desired)
+		if err := r.GitClient.RenameBranch(ctx, org, name, current, desired); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Updated", "renamed default branch from %s to %s", current, desired)
+		driftCorrections.WithLabelValues("Repository", "defaultBranch").Inc()
+	}
+
+	ghrepo.DefaultBranch = &desired
+	return nil
+}
+
+// initializedByController reports whether spec.init was used to create the
+// repository, generated, forked and imported repositories ignore it
+func initializedByController(repository *v1alpha1.Repository) bool {
+	return repository.Spec.FromTemplate == nil && repository.Spec.ForkFrom == nil && repository.Spec.Import == nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v28/github"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+)
+
+// branchClient answers the branch reads of the fake GitHub client and records
+// how the default branch is changed
+type branchClient struct {
+	git.Client
+	initialized bool
+	branches    map[string]bool
+	calls       []string
+}
+
+func (c *branchClient) RepoInitialized(ctx context.Context, org, name string) (bool, error) {
+	return c.initialized, nil
+}
+
+func (c *branchClient) BranchExists(ctx context.Context, org, name, branch string) (bool, error) {
+	return c.branches[branch], nil
+}
+
+func (c *branchClient) RenameBranch(ctx context.Context, org, name, branch, newName string) error {
+	c.calls = append(c.calls, "rename "+branch+" "+newName)
+	return c.Client.RenameBranch(ctx, org, name, branch, newName)
+}
+
+func (c *branchClient) EditRepo(ctx context.Context, org, name string, repo *github.Repository) (*github.Repository, error) {
+	c.calls = append(c.calls, "switch "+repo.GetDefaultBranch())
+	return c.Client.EditRepo(ctx, org, name, repo)
+}
+
+func TestReconcileDefaultBranch(t *testing.T) {
+	tests := []struct {
+		name            string
+		spec            v1alpha1.RepositorySpec
+		status          v1alpha1.RepositoryStatus
+		initialized     bool
+		branches        map[string]bool
+		expectedCalls   []string
+		expectedDefault string
+	}{
+		{
+			"in sync",
+			v1alpha1.RepositorySpec{DefaultBranch: "master"},
+			v1alpha1.RepositoryStatus{},
+			true,
+			nil,
+			nil,
+			"master",
+		},
+		{
+			"renames",
+			v1alpha1.RepositorySpec{DefaultBranch: "main"},
+			v1alpha1.RepositoryStatus{DefaultBranch: "master"},
+			true,
+			nil,
+			[]string{"rename master main"},
+			"main",
+		},
+		{
+			"switches instead of renaming onto an existing branch",
+			v1alpha1.RepositorySpec{DefaultBranch: "main"},
+			v1alpha1.RepositoryStatus{DefaultBranch: "master"},
+			true,
+			map[string]bool{"main": true},
+			[]string{"switch main"},
+			"main",
+		},
+		{
+			"switches",
+			v1alpha1.RepositorySpec{DefaultBranch: "main", DefaultBranchStrategy: v1alpha1.SwitchDefaultBranch},
+			v1alpha1.RepositoryStatus{DefaultBranch: "master"},
+			true,
+			nil,
+			[]string{"switch main"},
+			"main",
+		},
+		{
+			"waits for commits",
+			v1alpha1.RepositorySpec{DefaultBranch: "main"},
+			v1alpha1.RepositoryStatus{},
+			false,
+			nil,
+			nil,
+			"master",
+		},
+		{
+			"renames the initial branch",
+			v1alpha1.RepositorySpec{Init: v1alpha1.RepositoryInit{AutoInit: true, DefaultBranch: "main"}},
+			v1alpha1.RepositoryStatus{},
+			true,
+			nil,
+			[]string{"rename master main"},
+			"main",
+		},
+		{
+			"leaves the initial branch after the first sync",
+			v1alpha1.RepositorySpec{Init: v1alpha1.RepositoryInit{AutoInit: true, DefaultBranch: "main"}},
+			v1alpha1.RepositoryStatus{DefaultBranch: "master"},
+			true,
+			nil,
+			nil,
+			"master",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gitClient := &branchClient{Client: git.TestClient(), initialized: tt.initialized, branches: tt.branches}
+			r := testRepositoryReconciler(gitClient, false)
+
+			tt.spec.Organization, tt.spec.Name = "org", "repo"
+			repository := &v1alpha1.Repository{Spec: tt.spec, Status: tt.status}
+			ghrepo := &github.Repository{DefaultBranch: github.String("master")}
+			if err := r.reconcileDefaultBranch(context.Background(), repository, ghrepo); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(gitClient.calls, tt.expectedCalls) {
+				t.Errorf("expected %v, got %v", tt.expectedCalls, gitClient.calls)
+			}
+			if ghrepo.GetDefaultBranch() != tt.expectedDefault {
+				t.Errorf("expected default branch %s, got %s", tt.expectedDefault, ghrepo.GetDefaultBranch())
+			}
+		})
+	}
+}
@@ -19,14 +19,22 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	v1alpha1 "go.hein.dev/github-controller/api/v1alpha1"
 	"go.hein.dev/github-controller/git"
@@ -34,6 +42,10 @@ import (
 
 var (
 	repoFinalizerName = "repository.finalizers.github.go.hein.dev"
+
+	// initializationTimeout is how long a generated or forked repository may
+	// stay empty before the reconcile carries on without its contents
+	initializationTimeout = 5 * time.Minute
 )
 
 // RepositoryReconciler reconciles a Repository object
@@ -43,15 +55,40 @@ type RepositoryReconciler struct {
 	Scheme       *runtime.Scheme
 	GitClient    git.Client
 	ActualDelete bool
+
+	// Recorder records an event for every change made on GitHub
+	Recorder record.EventRecorder
+
+	// ReleasePausedOnDelete removes the finalizer of paused objects that are
+	// deleted instead of waiting for them to be resumed
+	ReleasePausedOnDelete bool
+
+	// GitHubEvents optionally triggers reconciles from GitHub webhook deliveries
+	GitHubEvents <-chan event.GenericEvent
+
+	// ImportTimeout bounds how long a repository import may run, defaults to 30 minutes
+	ImportTimeout time.Duration
+
+	// AllowInsecureImportSources allows importing from http:// and file:// sources
+	AllowInsecureImportSources bool
+
+	// Selector optionally limits the reconciled Repositories to the ones with matching labels
+	Selector labels.Selector
+
+	importsMu sync.Mutex
+	imports   map[types.NamespacedName]*importJob
 }
 
 // +kubebuilder:rbac:groups=github.go.hein.dev,resources=repositories,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=github.go.hein.dev,resources=repositories/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=github.go.hein.dev,resources=organizationpolicies,verbs=get;list;watch
 
 // Reconcile is responsible for reconciling the request
-func (r *RepositoryReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+func (r *RepositoryReconciler) Reconcile(req ctrl.Request) (result ctrl.Result, err error) {
 	var requeueafter time.Duration = 2 * time.Second
-	ctx := context.Background()
+	ctx, plan := git.WithPlan(context.Background())
 	log := r.Log.WithValues("repository", req.NamespacedName)
 
 	var repository v1alpha1.Repository
@@ -61,8 +98,21 @@ func (r *RepositoryReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		}
 		return ctrl.Result{}, err
 	}
+	if !selected(r.Selector, &repository) {
+		return ctrl.Result{}, nil
+	}
 
-	organizationRepo := fmt.Sprintf("%s/%s", repository.Spec.Organization, repository.Name)
+	organizationRepo := fmt.Sprintf("%s/%s", repository.Spec.Organization, repository.GitHubName())
+
+	// every failed call to GitHub surfaces on the object
+	defer func() {
+		if err != nil {
+			r.Recorder.Event(&repository, corev1.EventTypeWarning, "SyncFailed", err.Error())
+		}
+		if planErr := r.updateRepositoryPlan(ctx, &repository, plan); planErr != nil {
+			log.Error(planErr, "unable to update planned changes")
+		}
+	}()
 
 	log.Info("found local repository", "name", repository.Name)
 
@@ -75,6 +125,33 @@ func (r *RepositoryReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		return ctrl.Result{}, nil
 	}
 
+	// paused repositories are left alone on GitHub
+	paused, pauseErr := r.handlePaused(ctx, &repository)
+	if pauseErr != nil {
+		return ctrl.Result{}, pauseErr
+	}
+	if paused {
+		log.Info("reconciliation paused", "name", repository.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// repositories the organization policies don't allow are left alone on GitHub
+	allowed, policyErr := r.checkPolicy(ctx, &repository)
+	if policyErr != nil {
+		return ctrl.Result{}, policyErr
+	}
+	if !allowed {
+		log.Info("repository not allowed by organization policies", "name", repository.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// a changed reconcile-requested-at annotation reads everything fresh from GitHub
+	requestedAt := v1alpha1.ReconcileRequestedAt(&repository)
+	if requestedAt != "" && requestedAt != repository.Status.LastHandledReconcileAt {
+		log.Info("reconcile requested", "requestedAt", requestedAt)
+		ctx = git.WithFreshReads(ctx)
+	}
+
 	if !repository.ObjectMeta.DeletionTimestamp.IsZero() &&
 		containsString(repository.GetFinalizers(), repoFinalizerName) {
 		log.Info("handle deletion", "name", repository.Name)
@@ -85,13 +162,29 @@ func (r *RepositoryReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		return ctrl.Result{}, nil
 	}
 
-	repo, resp, err := r.GitClient.GetRepo(ctx, repository.Spec.Organization, repository.Name)
+	repo, resp, err := r.GitClient.GetRepo(ctx, repository.Spec.Organization, repository.GitHubName())
+
+	// the repository may have been renamed or transferred, find it by the ID it was synced with
+	if err != nil && isNotFound(resp) && repository.Status.GitHubID != 0 {
+		log.Info("respository not found by name, looking it up by id", "id", repository.Status.GitHubID)
+		repo, resp, err = r.GitClient.GetRepoByID(ctx, repository.Status.GitHubID)
+	}
 
 	if err != nil && isNotFound(resp) {
 		log.Info("respository not found", "creating", organizationRepo)
 		if err := r.GitClient.CreateRepo(ctx, repository.Spec.Organization, &repository); err != nil {
 			return ctrl.Result{}, err
 		}
+		r.Recorder.Eventf(&repository, corev1.EventTypeNormal, "Created", "created repository %s", organizationRepo)
+		// the dry-run client only planned the repository, it will never show up
+		if len(plan.Changes()) > 0 {
+			return ctrl.Result{}, nil
+		}
+		if initializedByController(&repository) {
+			if err := r.updateRepositoryInitStatus(ctx, &repository); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
 		return ctrl.Result{RequeueAfter: requeueafter}, nil
 	}
 
@@ -101,11 +194,103 @@ func (r *RepositoryReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 
 	log.Info("found remote repository", "name", organizationRepo)
 
+	// GitHub follows renames and transfers, move the repository to where the spec wants it
+	if moved, err := r.relocateRepository(ctx, &repository, repo); err != nil || moved {
+		// the dry-run client only planned the move, the repository stays where it is
+		if err == nil && len(plan.Changes()) > 0 {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: requeueafter}, err
+	}
+
+	// repositories generated from a template or forked are filled asynchronously
+	if (repository.Spec.FromTemplate != nil || repository.Spec.ForkFrom != nil) &&
+		repository.Status.Status != v1alpha1.SyncedStatus {
+		initialized, err := r.GitClient.RepoInitialized(ctx, repository.Spec.Organization, repository.GitHubName())
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		switch {
+		case initialized:
+		case time.Since(repo.GetCreatedAt().Time) > initializationTimeout:
+			log.Info("repository still empty, continuing without contents", "source", repositorySource(&repository))
+			r.Recorder.Eventf(&repository, corev1.EventTypeWarning, "InitializationTimeout", "%s has no commits %s after it was created from %s, continuing without them",
+				organizationRepo, initializationTimeout, repositorySource(&repository))
+		default:
+			log.Info("waiting for repository contents", "source", repositorySource(&repository))
+			return ctrl.Result{RequeueAfter: requeueafter}, nil
+		}
+	}
+
 	// TODO: (christopherhein) Update Repo Checks
 
+	imported, err := r.reconcileImport(ctx, &repository, repo)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !imported && !importBlocked(&repository) {
+		return ctrl.Result{RequeueAfter: importPollInterval}, nil
+	}
+	if !imported {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.reconcileCustomProperties(ctx, &repository); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileForkSettings(ctx, &repository, repo); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileForkStatus(ctx, &repository, repo); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileDefaultBranch(ctx, &repository, repo); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileVariables(ctx, &repository); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileEnvironments(ctx, &repository); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileSecurity(ctx, &repository); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileLabels(ctx, &repository); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileAutolinks(ctx, &repository); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// a Pages site GitHub refuses to configure doesn't hold back the status
+	pagesErr := r.reconcilePages(ctx, &repository, repo)
+	if pagesErr != nil {
+		log.Error(pagesErr, "unable to reconcile pages")
+	}
+
+	// the dry-run client only planned the changes, the managed lists and the
+	// Synced status would claim they were made
+	if changes := plan.Changes(); len(changes) > 0 {
+		log.Info("planned repository changes", "count", len(changes))
+		return ctrl.Result{}, pagesErr
+	}
+
+	repository.Status.LastHandledReconcileAt = requestedAt
 	if err := r.updateRepositoryStatusDetails(ctx, repo, &repository); err != nil {
 		return ctrl.Result{}, err
 	}
+	if pagesErr != nil {
+		return ctrl.Result{}, pagesErr
+	}
 
 	log.Info("updated local repo repository")
 
@@ -114,7 +299,22 @@ func (r *RepositoryReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 
 // SetupWithManager configures the controller
 func (r *RepositoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("repository-controller")
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.Repository{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &v1alpha1.OrganizationPolicy{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.policyToRepositories),
+		})
+
+	if r.GitHubEvents != nil {
+		builder = builder.Watches(&source.Channel{Source: r.GitHubEvents}, &handler.EnqueueRequestForObject{})
+	}
+	if r.Selector != nil {
+		builder = builder.WithEventFilter(selectorPredicate(r.Selector, &v1alpha1.Repository{}))
+	}
+
+	return builder.Complete(r)
 }
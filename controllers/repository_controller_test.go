@@ -22,6 +22,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	"go.hein.dev/github-controller/api/v1alpha1"
@@ -95,6 +96,146 @@ var _ = Describe("Run Controller", func() {
 				k8sClient.Get(context.Background(), repokey, r)
 				return len(r.GetFinalizers()) == 0
 			}, timeout, interval).Should(BeTrue())
+
+			By("Describing the deletion event")
+			Eventually(func() bool {
+				return hasEvent(repokey.Namespace, repokey.Name, "Deleted")
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+
+	Context("Run a paused Repository", func() {
+		It("Should only be deleted once resumed", func() {
+			repokey := types.NamespacedName{Name: "test-paused-repo", Namespace: "default"}
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        repokey.Name,
+					Namespace:   repokey.Namespace,
+					Annotations: map[string]string{v1alpha1.PausedAnnotation: "true"},
+				},
+				Spec: v1alpha1.RepositorySpec{
+					Organization: "awsctrl",
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), repo)).Should(Succeed())
+
+			By("Describing the Paused condition")
+			Eventually(func() bool {
+				r := &v1alpha1.Repository{}
+				k8sClient.Get(context.Background(), repokey, r)
+				condition := v1alpha1.FindCondition(r.Status.Conditions, v1alpha1.PausedCondition)
+				return len(r.GetFinalizers()) == 1 && condition != nil && condition.Status == corev1.ConditionTrue
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() bool {
+				return hasEvent(repokey.Namespace, repokey.Name, "Paused")
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(k8sClient.Delete(context.Background(), repo)).Should(Succeed())
+
+			By("Describing the paused deletion")
+			Eventually(func() bool {
+				return hasEvent(repokey.Namespace, repokey.Name, deletePausedReason)
+			}, timeout, interval).Should(BeTrue())
+			Consistently(func() bool {
+				r := &v1alpha1.Repository{}
+				k8sClient.Get(context.Background(), repokey, r)
+				return len(r.GetFinalizers()) == 1
+			}, time.Second, interval).Should(BeTrue())
+			Expect(hasEvent(repokey.Namespace, repokey.Name, "Deleted")).Should(BeFalse())
+
+			By("Describing the deletion once resumed")
+			Eventually(func() error {
+				r := &v1alpha1.Repository{}
+				if err := k8sClient.Get(context.Background(), repokey, r); err != nil {
+					return err
+				}
+				delete(r.Annotations, v1alpha1.PausedAnnotation)
+				return k8sClient.Update(context.Background(), r)
+			}, timeout, interval).Should(Succeed())
+			Eventually(func() bool {
+				return isGone(repokey, &v1alpha1.Repository{})
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() bool {
+				return hasEvent(repokey.Namespace, repokey.Name, "Deleted")
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+
+	Context("Run a Repository the organization policies deny", func() {
+		It("Should be released without deleting it", func() {
+			policy := &v1alpha1.OrganizationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-policy"},
+				Spec: v1alpha1.OrganizationPolicySpec{
+					Organization: "policyorg",
+					Namespaces:   []string{"other"},
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), policy)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), policy)).Should(Succeed())
+			}()
+
+			repokey := types.NamespacedName{Name: "test-denied-repo", Namespace: "default"}
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      repokey.Name,
+					Namespace: repokey.Namespace,
+				},
+				Spec: v1alpha1.RepositorySpec{
+					Organization: "policyorg",
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), repo)).Should(Succeed())
+
+			By("Describing the Allowed condition")
+			Eventually(func() bool {
+				r := &v1alpha1.Repository{}
+				k8sClient.Get(context.Background(), repokey, r)
+				condition := v1alpha1.FindCondition(r.Status.Conditions, v1alpha1.AllowedCondition)
+				return condition != nil && condition.Status == corev1.ConditionFalse
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() bool {
+				return hasEvent(repokey.Namespace, repokey.Name, policyViolationReason)
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(k8sClient.Delete(context.Background(), repo)).Should(Succeed())
+
+			By("Describing the release")
+			Eventually(func() bool {
+				return isGone(repokey, &v1alpha1.Repository{})
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() bool {
+				return hasEvent(repokey.Namespace, repokey.Name, "DeleteSkipped")
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+
+	Context("Run a Repository the label selector skips", func() {
+		It("Should not be reconciled", func() {
+			repokey := types.NamespacedName{Name: "test-ignored-repo", Namespace: "default"}
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      repokey.Name,
+					Namespace: repokey.Namespace,
+					Labels:    map[string]string{ignoredLabel: "true"},
+				},
+				Spec: v1alpha1.RepositorySpec{
+					Organization: "awsctrl",
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), repo)).Should(Succeed())
+
+			By("Describing the untouched Repository")
+			Consistently(func() bool {
+				r := &v1alpha1.Repository{}
+				k8sClient.Get(context.Background(), repokey, r)
+				return len(r.GetFinalizers()) == 0 && r.Status.Status == ""
+			}, time.Second*2, interval).Should(BeTrue())
+
+			Expect(k8sClient.Delete(context.Background(), repo)).Should(Succeed())
+			Eventually(func() bool {
+				return isGone(repokey, &v1alpha1.Repository{})
+			}, timeout, interval).Should(BeTrue())
 		})
 	})
 })
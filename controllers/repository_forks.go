@@ -0,0 +1,110 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v28/github"
+	"go.hein.dev/github-controller/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// reconcileForkSettings applies the settings of the spec to a new fork, GitHub
+// creates forks with the settings of the parent. Only the differing settings
+// are sent as forks can't change every setting, a fork keeps the visibility of
+// its parent so a different one in the spec is only reported.
+func (r *RepositoryReconciler) reconcileForkSettings(ctx context.Context, repository *v1alpha1.Repository, ghrepo *github.Repository) error {
+	if repository.Spec.ForkFrom == nil || repository.Status.Status == v1alpha1.SyncedStatus {
+		return nil
+	}
+
+	spec := repository.Spec
+	edit := &github.Repository{}
+	changed := false
+	if ghrepo.GetDescription() != spec.Description {
+		edit.Description, changed = github.String(spec.Description), true
+	}
+	if ghrepo.GetHomepage() != spec.Homepage {
+		edit.Homepage, changed = github.String(spec.Homepage), true
+	}
+	if ghrepo.GetHasIssues() != spec.Settings.Issues {
+		edit.HasIssues, changed = github.Bool(spec.Settings.Issues), true
+	}
+	if ghrepo.GetHasWiki() != spec.Settings.Wiki {
+		edit.HasWiki, changed = github.Bool(spec.Settings.Wiki), true
+	}
+	if ghrepo.GetHasProjects() != spec.Settings.Projects {
+		edit.HasProjects, changed = github.Bool(spec.Settings.Projects), true
+	}
+	if ghrepo.GetIsTemplate() != spec.Settings.Template {
+		edit.IsTemplate, changed = github.Bool(spec.Settings.Template), true
+	}
+	org, name := spec.Organization, repository.GitHubName()
+	if ghrepo.GetPrivate() != spec.Settings.Private {
+		r.Recorder.Eventf(repository, corev1.EventTypeWarning, "VisibilityMismatch", "fork %s/%s has private=%t but the spec sets private=%t, GitHub doesn't change the visibility of forks", org, name, ghrepo.GetPrivate(), spec.Settings.Private)
+	}
+	if !changed {
+		return nil
+	}
+
+	r.Log.Info("applying settings to fork", "repository", org+"/"+name)
+	if _, err := r.GitClient.EditRepo(ctx, org, name, edit); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Updated", "applied the settings of the spec to fork %s/%s", org, name)
+	return nil
+}
+
+// reconcileForkStatus records the parent of a fork and how far its default
+// branch diverged from the default branch of the parent
+func (r *RepositoryReconciler) reconcileForkStatus(ctx context.Context, repository *v1alpha1.Repository, ghrepo *github.Repository) error {
+	parent := ghrepo.GetParent()
+	if !ghrepo.GetFork() || parent == nil {
+		repository.Status.Fork = nil
+		return nil
+	}
+
+	parentOwner, parentName := parent.GetOwner().GetLogin(), parent.GetName()
+	head := fmt.Sprintf("%s:%s", ghrepo.GetOwner().GetLogin(), ghrepo.GetDefaultBranch())
+	ahead, behind, err := r.GitClient.CompareCommits(ctx, parentOwner, parentName, parent.GetDefaultBranch(), head)
+	if err != nil {
+		return err
+	}
+
+	repository.Status.Fork = &v1alpha1.RepositoryForkStatus{
+		Parent:   parent.GetFullName(),
+		AheadBy:  ahead,
+		BehindBy: behind,
+	}
+	if repository.Status.Fork.Parent == "" {
+		repository.Status.Fork.Parent = fmt.Sprintf("%s/%s", parentOwner, parentName)
+	}
+	return nil
+}
+
+// repositorySource names the repository a repository is generated or forked from
+func repositorySource(repository *v1alpha1.Repository) string {
+	switch {
+	case repository.Spec.FromTemplate != nil:
+		return fmt.Sprintf("%s/%s", repository.Spec.FromTemplate.Owner, repository.Spec.FromTemplate.Repository)
+	case repository.Spec.ForkFrom != nil:
+		return fmt.Sprintf("%s/%s", repository.Spec.ForkFrom.Owner, repository.Spec.ForkFrom.Repository)
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v28/github"
+	"k8s.io/client-go/tools/record"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+)
+
+// editRecordingClient records the edits sent to the fake GitHub client
+type editRecordingClient struct {
+	git.Client
+	edits []*github.Repository
+}
+
+func (c *editRecordingClient) EditRepo(ctx context.Context, org, name string, repo *github.Repository) (*github.Repository, error) {
+	c.edits = append(c.edits, repo)
+	return c.Client.EditRepo(ctx, org, name, repo)
+}
+
+// recordedEvents drains the events of a fake recorder
+func recordedEvents(recorder record.EventRecorder) []string {
+	events := []string{}
+	for {
+		select {
+		case event := <-recorder.(*record.FakeRecorder).Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestReconcileForkSettings(t *testing.T) {
+	fork := &v1alpha1.RepositoryFork{Owner: "upstream", Repository: "repo"}
+	settings := v1alpha1.RepositorySettings{Issues: true, Wiki: true}
+
+	tests := []struct {
+		name          string
+		spec          v1alpha1.RepositorySpec
+		status        v1alpha1.StatusReason
+		expectedEdits []*github.Repository
+		expectedEvent string
+	}{
+		{
+			"not a fork",
+			v1alpha1.RepositorySpec{Description: "changed", Settings: settings},
+			"",
+			nil,
+			"",
+		},
+		{
+			"in sync",
+			v1alpha1.RepositorySpec{ForkFrom: fork, Description: "fork", Settings: settings},
+			"",
+			nil,
+			"",
+		},
+		{
+			"differing settings",
+			v1alpha1.RepositorySpec{ForkFrom: fork, Description: "changed", Homepage: "https://example.com", Settings: v1alpha1.RepositorySettings{Issues: true}},
+			"",
+			[]*github.Repository{{Description: github.String("changed"), Homepage: github.String("https://example.com"), HasWiki: github.Bool(false)}},
+			"Normal Updated",
+		},
+		{
+			"differing visibility",
+			v1alpha1.RepositorySpec{ForkFrom: fork, Description: "fork", Settings: v1alpha1.RepositorySettings{Issues: true, Wiki: true, Private: true}},
+			"",
+			nil,
+			"Warning VisibilityMismatch",
+		},
+		{
+			"already synced",
+			v1alpha1.RepositorySpec{ForkFrom: fork, Description: "changed", Settings: settings},
+			v1alpha1.SyncedStatus,
+			nil,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gitClient := &editRecordingClient{Client: git.TestClient()}
+			r := testRepositoryReconciler(gitClient, false)
+
+			tt.spec.Organization, tt.spec.Name = "org", "repo"
+			repository := &v1alpha1.Repository{Spec: tt.spec, Status: v1alpha1.RepositoryStatus{Status: tt.status}}
+			ghrepo := &github.Repository{
+				Description: github.String("fork"),
+				HasIssues:   github.Bool(true),
+				HasWiki:     github.Bool(true),
+				Private:     github.Bool(false),
+			}
+			if err := r.reconcileForkSettings(context.Background(), repository, ghrepo); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(gitClient.edits, tt.expectedEdits) {
+				t.Errorf("expected edits %+v, got %+v", tt.expectedEdits, gitClient.edits)
+			}
+			events := recordedEvents(r.Recorder)
+			switch {
+			case tt.expectedEvent == "" && len(events) > 0:
+				t.Errorf("expected no events, got %v", events)
+			case tt.expectedEvent != "" && (len(events) != 1 || !strings.HasPrefix(events[0], tt.expectedEvent)):
+				t.Errorf("expected a %s event, got %v", tt.expectedEvent, events)
+			}
+		})
+	}
+}
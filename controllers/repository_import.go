@@ -0,0 +1,196 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v28/github"
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/mirror"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// importingReason means the import is running
+	importingReason = "Importing"
+
+	// importFailedReason means the last import attempt failed and is retried
+	importFailedReason = "ImportFailed"
+
+	// importSucceededReason means every branch and tag was imported
+	importSucceededReason = "ImportSucceeded"
+
+	// repositoryNotEmptyReason means the repository already had commits and is never overwritten
+	repositoryNotEmptyReason = "RepositoryNotEmpty"
+
+	// invalidSourceReason means the source URL is not allowed and the import is not attempted
+	invalidSourceReason = "InvalidSource"
+
+	// defaultImportTimeout bounds imports when the reconciler doesn't set ImportTimeout
+	defaultImportTimeout = 30 * time.Minute
+
+	// importPollInterval is how often a running import is checked on
+	importPollInterval = 10 * time.Second
+)
+
+// importJob is an import running in the background, err is set before done is closed
+type importJob struct {
+	done chan struct{}
+	err  error
+}
+
+// reconcileImport copies the branches and tags of the source repository into
+// the repository once, it reports false while the import did not succeed. The
+// import runs in the background so a large or hanging source doesn't hold up
+// the other repositories, its result is picked up by a later reconcile.
+func (r *RepositoryReconciler) reconcileImport(ctx context.Context, repository *v1alpha1.Repository, ghrepo *github.Repository) (bool, error) {
+	source := repository.Spec.Import
+	if source == nil {
+		return true, nil
+	}
+
+	condition := v1alpha1.FindCondition(repository.Status.Conditions, v1alpha1.ImportedCondition)
+	if condition != nil && (condition.Status == corev1.ConditionTrue || condition.Reason == repositoryNotEmptyReason) {
+		return true, nil
+	}
+
+	org, name := repository.Spec.Organization, repository.GitHubName()
+	log := r.Log.WithValues("repository", org+"/"+name)
+
+	// a running import is checked on until it finishes
+	key := types.NamespacedName{Namespace: repository.Namespace, Name: repository.Name}
+	if job := r.runningImport(key); job != nil {
+		select {
+		case <-job.done:
+		default:
+			log.Info("import still running", "source", source.URL)
+			return false, nil
+		}
+
+		r.forgetImport(key)
+		if job.err != nil {
+			if statusErr := r.setRepositoryCondition(ctx, repository, corev1.ConditionFalse, importFailedReason, job.err.Error()); statusErr != nil {
+				log.Error(statusErr, "unable to update import condition")
+			}
+			return false, job.err
+		}
+		log.Info("imported repository", "source", source.URL)
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Imported", "imported %s", source.URL)
+		return true, r.setRepositoryCondition(ctx, repository, corev1.ConditionTrue, importSucceededReason, "imported "+source.URL)
+	}
+
+	// sources that could read the filesystem of the controller are only fetched when allowed
+	if err := mirror.ValidateSource(source.URL, r.AllowInsecureImportSources); err != nil {
+		log.Info("invalid import source", "source", source.URL, "reason", err.Error())
+		return false, r.setRepositoryCondition(ctx, repository, corev1.ConditionFalse, invalidSourceReason, err.Error())
+	}
+
+	// only retry imports the controller started, history pushed by others is never overwritten
+	if condition == nil || condition.Reason == invalidSourceReason {
+		initialized, err := r.GitClient.RepoInitialized(ctx, org, name)
+		if err != nil {
+			return false, err
+		}
+		if initialized {
+			log.Info("skipping import into a repository with commits", "source", source.URL)
+			r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Skipped", "skipped importing %s into a repository with commits", source.URL)
+			return true, r.setRepositoryCondition(ctx, repository, corev1.ConditionFalse, repositoryNotEmptyReason,
+				"the repository already had commits when the import was requested")
+		}
+	}
+
+	var creds *mirror.Credentials
+	if source.CredentialsSecretRef != "" {
+		var secret corev1.Secret
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: source.CredentialsSecretRef, Namespace: repository.Namespace}, &secret); err != nil {
+			if errors.IsNotFound(err) {
+				log.Info("import credentials secret does not exist", "secret", source.CredentialsSecretRef)
+				return false, nil
+			}
+			return false, err
+		}
+		creds = &mirror.Credentials{
+			Username: string(secret.Data[corev1.BasicAuthUsernameKey]),
+			Password: string(secret.Data[corev1.BasicAuthPasswordKey]),
+		}
+	}
+
+	if err := r.setRepositoryCondition(ctx, repository, corev1.ConditionFalse, importingReason, "importing "+source.URL); err != nil {
+		return false, err
+	}
+
+	log.Info("importing repository", "source", source.URL)
+	r.startImport(ctx, key, ghrepo.GetCloneURL(), source.URL, creds)
+	return false, nil
+}
+
+// importBlocked reports whether the import can't run until the spec changes
+func importBlocked(repository *v1alpha1.Repository) bool {
+	condition := v1alpha1.FindCondition(repository.Status.Conditions, v1alpha1.ImportedCondition)
+	return condition != nil && condition.Reason == invalidSourceReason
+}
+
+// startImport runs the import in the background bounded by the ImportTimeout
+func (r *RepositoryReconciler) startImport(ctx context.Context, key types.NamespacedName, cloneURL, source string, creds *mirror.Credentials) {
+	timeout := r.ImportTimeout
+	if timeout == 0 {
+		timeout = defaultImportTimeout
+	}
+
+	job := &importJob{done: make(chan struct{})}
+	r.importsMu.Lock()
+	if r.imports == nil {
+		r.imports = map[types.NamespacedName]*importJob{}
+	}
+	r.imports[key] = job
+	r.importsMu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		job.err = r.GitClient.ImportRepo(ctx, cloneURL, source, creds)
+		close(job.done)
+	}()
+}
+
+func (r *RepositoryReconciler) runningImport(key types.NamespacedName) *importJob {
+	r.importsMu.Lock()
+	defer r.importsMu.Unlock()
+	return r.imports[key]
+}
+
+func (r *RepositoryReconciler) forgetImport(key types.NamespacedName) {
+	r.importsMu.Lock()
+	defer r.importsMu.Unlock()
+	delete(r.imports, key)
+}
+
+// setRepositoryCondition sets the Imported condition and stores it right away
+// so the progress is visible while the import runs
+func (r *RepositoryReconciler) setRepositoryCondition(ctx context.Context, repository *v1alpha1.Repository, status corev1.ConditionStatus, reason, message string) error {
+	v1alpha1.SetCondition(&repository.Status.Conditions, v1alpha1.Condition{
+		Type:    v1alpha1.ImportedCondition,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	return r.updateRepositoryConditions(ctx, repository)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-github/v28/github"
+	"go.hein.dev/github-controller/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// reconcileLabels creates and updates the labels in the spec and removes the
+// ones that are no longer in the spec, unmanaged labels are only removed when
+// pruning is enabled. Nothing is removed without ActualDelete, managed labels
+// that are kept stay managed until they are removed.
+func (r *RepositoryReconciler) reconcileLabels(ctx context.Context, repository *v1alpha1.Repository) error {
+	if len(repository.Spec.Labels) == 0 && len(repository.Status.ManagedLabels) == 0 {
+		return nil
+	}
+
+	org, name := repository.Spec.Organization, repository.GitHubName()
+	log := r.Log.WithValues("repository", org+"/"+name)
+
+	existing, err := r.GitClient.ListLabels(ctx, org, name)
+	if err != nil {
+		return err
+	}
+
+	// label names are case insensitive
+	observed := map[string]*github.Label{}
+	for _, label := range existing {
+		observed[strings.ToLower(label.GetName())] = label
+	}
+
+	desired := map[string]bool{}
+	managed := []string{}
+	for _, label := range repository.Spec.Labels {
+		desired[strings.ToLower(label.Name)] = true
+		managed = append(managed, label.Name)
+
+		ghLabel := &github.Label{
+			Name:        github.String(label.Name),
+			Color:       github.String(strings.ToLower(label.Color)),
+			Description: github.String(label.Description),
+		}
+
+		current, ok := observed[strings.ToLower(label.Name)]
+		switch {
+		case !ok:
+			log.Info("creating label", "label", label.Name)
+			if err = r.GitClient.CreateLabel(ctx, org, name, ghLabel); err == nil {
+				r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Created", "created label %s", label.Name)
+				driftCorrections.WithLabelValues("Repository", "labels").Inc()
+			}
+		case !labelMatches(current, &label):
+			log.Info("updating label", "label", label.Name)
+			if err = r.GitClient.EditLabel(ctx, org, name, current.GetName(), ghLabel); err == nil {
+				r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Updated", "updated label %s", label.Name)
+				driftCorrections.WithLabelValues("Repository", "labels").Inc()
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	previouslyManaged := map[string]bool{}
+	for _, label := range repository.Status.ManagedLabels {
+		previouslyManaged[strings.ToLower(label)] = true
+	}
+
+	pruneUnmanaged := len(repository.Spec.Labels) > 0 && repository.Spec.PruneUnmanagedLabels
+	for _, label := range existing {
+		key := strings.ToLower(label.GetName())
+		if desired[key] || (!pruneUnmanaged && !previouslyManaged[key]) {
+			continue
+		}
+		if !r.ActualDelete {
+			r.Recorder.Eventf(repository, corev1.EventTypeNormal, "DeleteSkipped", "kept label %s, --actual-delete is off", label.GetName())
+			if previouslyManaged[key] {
+				managed = append(managed, label.GetName())
+			}
+			continue
+		}
+		log.Info("deleting label", "label", label.GetName())
+		if err := r.GitClient.DeleteLabel(ctx, org, name, label.GetName()); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Deleted", "deleted label %s", label.GetName())
+		driftCorrections.WithLabelValues("Repository", "labels").Inc()
+	}
+
+	repository.Status.ManagedLabels = managed
+	return nil
+}
+
+// labelMatches reports whether the observed label has the name, color and
+// description of the desired one
+func labelMatches(current *github.Label, desired *v1alpha1.RepositoryLabel) bool {
+	return current.GetName() == desired.Name &&
+		strings.EqualFold(current.GetColor(), desired.Color) &&
+		current.GetDescription() == desired.Description
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/google/go-github/v28/github"
+	"k8s.io/client-go/tools/record"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+)
+
+// testRepositoryReconciler returns a reconciler changing the fake GitHub client
+func testRepositoryReconciler(gitClient git.Client, actualDelete bool) *RepositoryReconciler {
+	return &RepositoryReconciler{
+		Log:          logf.NullLogger{},
+		GitClient:    gitClient,
+		ActualDelete: actualDelete,
+		Recorder:     record.NewFakeRecorder(100),
+	}
+}
+
+func TestLabelMatches(t *testing.T) {
+	current := &github.Label{Name: github.String("bug"), Color: github.String("d73a4a"), Description: github.String("Something isn't working")}
+
+	tests := []struct {
+		name     string
+		desired  v1alpha1.RepositoryLabel
+		expected bool
+	}{
+		{"same", v1alpha1.RepositoryLabel{Name: "bug", Color: "d73a4a", Description: "Something isn't working"}, true},
+		{"color case", v1alpha1.RepositoryLabel{Name: "bug", Color: "D73A4A", Description: "Something isn't working"}, true},
+		{"name case", v1alpha1.RepositoryLabel{Name: "Bug", Color: "d73a4a", Description: "Something isn't working"}, false},
+		{"color", v1alpha1.RepositoryLabel{Name: "bug", Color: "ffffff", Description: "Something isn't working"}, false},
+		{"description", v1alpha1.RepositoryLabel{Name: "bug", Color: "d73a4a"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := labelMatches(current, &tt.desired); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestReconcileLabels(t *testing.T) {
+	tests := []struct {
+		name            string
+		actualDelete    bool
+		prune           bool
+		expectedLabels  []string
+		expectedManaged []string
+	}{
+		{"keeps removed labels managed", false, false, []string{"bug", "stale", "unmanaged"}, []string{"bug", "stale"}},
+		{"deletes removed labels", true, false, []string{"bug", "unmanaged"}, []string{"bug"}},
+		{"prunes unmanaged labels", true, true, []string{"bug"}, []string{"bug"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			gitClient := git.TestClient()
+			for _, name := range []string{"bug", "stale", "unmanaged"} {
+				if err := gitClient.CreateLabel(ctx, "org", "repo", &github.Label{Name: github.String(name), Color: github.String("ffffff")}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			repository := &v1alpha1.Repository{
+				Spec: v1alpha1.RepositorySpec{
+					Organization:         "org",
+					Name:                 "repo",
+					Labels:               []v1alpha1.RepositoryLabel{{Name: "bug", Color: "d73a4a"}},
+					PruneUnmanagedLabels: tt.prune,
+				},
+				Status: v1alpha1.RepositoryStatus{ManagedLabels: []string{"bug", "stale"}},
+			}
+			if err := testRepositoryReconciler(gitClient, tt.actualDelete).reconcileLabels(ctx, repository); err != nil {
+				t.Fatal(err)
+			}
+
+			labels, err := gitClient.ListLabels(ctx, "org", "repo")
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := []string{}
+			for _, label := range labels {
+				names = append(names, label.GetName())
+				if label.GetName() == "bug" && label.GetColor() != "d73a4a" {
+					t.Errorf("expected the bug label to be updated, got color %s", label.GetColor())
+				}
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tt.expectedLabels) {
+				t.Errorf("expected labels %v, got %v", tt.expectedLabels, names)
+			}
+			if !reflect.DeepEqual(repository.Status.ManagedLabels, tt.expectedManaged) {
+				t.Errorf("expected managed labels %v, got %v", tt.expectedManaged, repository.Status.ManagedLabels)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/google/go-github/v28/github"
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// reconcilePages enables and updates the Pages site in the spec, a site that
+// was managed before is disabled once it is removed from the spec, without
+// ActualDelete it is kept and stays managed
+func (r *RepositoryReconciler) reconcilePages(ctx context.Context, repository *v1alpha1.Repository, ghrepo *github.Repository) error {
+	org, name := repository.Spec.Organization, repository.GitHubName()
+	log := r.Log.WithValues("repository", org+"/"+name)
+
+	if repository.Spec.Pages == nil {
+		if repository.Status.Pages != nil {
+			if !r.ActualDelete {
+				r.Recorder.Event(repository, corev1.EventTypeNormal, "DeleteSkipped", "kept pages, --actual-delete is off")
+				return nil
+			}
+			log.Info("disabling pages")
+			if err := r.GitClient.DeletePages(ctx, org, name); err != nil {
+				return err
+			}
+			r.Recorder.Event(repository, corev1.EventTypeNormal, "Deleted", "disabled pages")
+			driftCorrections.WithLabelValues("Repository", "pages").Inc()
+		}
+		repository.Status.Pages = nil
+		return nil
+	}
+
+	desired := desiredPages(repository.Spec.Pages, ghrepo.GetDefaultBranch())
+
+	current, err := r.GitClient.GetPages(ctx, org, name)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		log.Info("enabling pages", "buildType", desired.BuildType)
+		if err := r.GitClient.CreatePages(ctx, org, name, desired); err != nil {
+			return err
+		}
+		r.Recorder.Event(repository, corev1.EventTypeNormal, "Created", "enabled pages")
+		driftCorrections.WithLabelValues("Repository", "pages").Inc()
+		if current, err = r.GitClient.GetPages(ctx, org, name); err != nil {
+			return err
+		}
+	}
+
+	if current != nil && !pagesMatch(current, desired) {
+		log.Info("updating pages")
+		if err := r.GitClient.UpdatePages(ctx, org, name, desired); err != nil {
+			return err
+		}
+		r.Recorder.Event(repository, corev1.EventTypeNormal, "Updated", "updated pages")
+		driftCorrections.WithLabelValues("Repository", "pages").Inc()
+		if current, err = r.GitClient.GetPages(ctx, org, name); err != nil {
+			return err
+		}
+	}
+
+	repository.Status.Pages = &v1alpha1.RepositoryPagesStatus{}
+	if current != nil {
+		repository.Status.Pages.URL = current.HTMLURL
+		repository.Status.Pages.BuildStatus = current.Status
+	}
+	return nil
+}
+
+// desiredPages converts the spec to the Pages API, legacy sites default to
+// the root of the default branch
+func desiredPages(spec *v1alpha1.RepositoryPages, defaultBranch string) *git.Pages {
+	pages := &git.Pages{
+		BuildType:     string(spec.BuildType),
+		HTTPSEnforced: spec.HTTPSEnforced,
+	}
+	if pages.BuildType == "" {
+		pages.BuildType = string(v1alpha1.LegacyPagesBuild)
+	}
+	if spec.CNAME != "" {
+		pages.CNAME = &spec.CNAME
+	}
+
+	if pages.BuildType == string(v1alpha1.LegacyPagesBuild) {
+		pages.Source = &git.PagesSource{Branch: defaultBranch, Path: "/"}
+		if spec.Source != nil {
+			pages.Source.Branch = spec.Source.Branch
+			if spec.Source.Path != "" {
+				pages.Source.Path = spec.Source.Path
+			}
+		}
+	}
+	return pages
+}
+
+// pagesMatch reports whether the observed site has the settings of the desired one
+func pagesMatch(current, desired *git.Pages) bool {
+	if current.BuildType != desired.BuildType ||
+		stringValue(current.CNAME) != stringValue(desired.CNAME) {
+		return false
+	}
+
+	// HTTPS is only compared when the spec sets it
+	if desired.HTTPSEnforced != nil &&
+		(current.HTTPSEnforced == nil || *current.HTTPSEnforced != *desired.HTTPSEnforced) {
+		return false
+	}
+
+	if desired.Source == nil {
+		return true
+	}
+	return current.Source != nil &&
+		current.Source.Branch == desired.Source.Branch &&
+		current.Source.Path == desired.Source.Path
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v28/github"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+)
+
+func TestDesiredPages(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     v1alpha1.RepositoryPages
+		expected *git.Pages
+	}{
+		{
+			"legacy from the default branch",
+			v1alpha1.RepositoryPages{},
+			&git.Pages{BuildType: "legacy", Source: &git.PagesSource{Branch: "main", Path: "/"}},
+		},
+		{
+			"legacy from a source",
+			v1alpha1.RepositoryPages{Source: &v1alpha1.PagesSource{Branch: "gh-pages", Path: "/docs"}, CNAME: "docs.example.com"},
+			&git.Pages{BuildType: "legacy", Source: &git.PagesSource{Branch: "gh-pages", Path: "/docs"}, CNAME: github.String("docs.example.com")},
+		},
+		{
+			"legacy from the root of a source",
+			v1alpha1.RepositoryPages{Source: &v1alpha1.PagesSource{Branch: "gh-pages"}},
+			&git.Pages{BuildType: "legacy", Source: &git.PagesSource{Branch: "gh-pages", Path: "/"}},
+		},
+		{
+			"workflow",
+			v1alpha1.RepositoryPages{BuildType: v1alpha1.WorkflowPagesBuild, HTTPSEnforced: github.Bool(true)},
+			&git.Pages{BuildType: "workflow", HTTPSEnforced: github.Bool(true)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := desiredPages(&tt.spec, "main"); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPagesMatch(t *testing.T) {
+	desired := &git.Pages{BuildType: "legacy", Source: &git.PagesSource{Branch: "main", Path: "/"}, CNAME: github.String("docs.example.com")}
+
+	tests := []struct {
+		name     string
+		current  *git.Pages
+		desired  *git.Pages
+		expected bool
+	}{
+		{
+			"same",
+			&git.Pages{BuildType: "legacy", Source: &git.PagesSource{Branch: "main", Path: "/"}, CNAME: github.String("docs.example.com"), HTTPSEnforced: github.Bool(true)},
+			desired,
+			true,
+		},
+		{
+			"cname",
+			&git.Pages{BuildType: "legacy", Source: &git.PagesSource{Branch: "main", Path: "/"}},
+			desired,
+			false,
+		},
+		{
+			"source",
+			&git.Pages{BuildType: "legacy", Source: &git.PagesSource{Branch: "main", Path: "/docs"}, CNAME: github.String("docs.example.com")},
+			desired,
+			false,
+		},
+		{
+			"build type",
+			&git.Pages{BuildType: "workflow", CNAME: github.String("docs.example.com")},
+			desired,
+			false,
+		},
+		{
+			"https left to github",
+			&git.Pages{BuildType: "workflow", HTTPSEnforced: github.Bool(false)},
+			&git.Pages{BuildType: "workflow"},
+			true,
+		},
+		{
+			"https",
+			&git.Pages{BuildType: "workflow", HTTPSEnforced: github.Bool(false)},
+			&git.Pages{BuildType: "workflow", HTTPSEnforced: github.Bool(true)},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := pagesMatch(tt.current, tt.desired); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestReconcilePages(t *testing.T) {
+	tests := []struct {
+		name           string
+		spec           *v1alpha1.RepositoryPages
+		actualDelete   bool
+		expectedPages  *git.Pages
+		expectedStatus *v1alpha1.RepositoryPagesStatus
+	}{
+		{
+			"enables and updates the site",
+			&v1alpha1.RepositoryPages{CNAME: "docs.example.com"},
+			false,
+			&git.Pages{
+				HTMLURL:   "https://org.github.io/repo/",
+				Status:    "building",
+				BuildType: "legacy",
+				Source:    &git.PagesSource{Branch: "main", Path: "/"},
+				CNAME:     github.String("docs.example.com"),
+			},
+			&v1alpha1.RepositoryPagesStatus{URL: "https://org.github.io/repo/", BuildStatus: "building"},
+		},
+		{
+			"keeps a removed site",
+			nil,
+			false,
+			&git.Pages{HTMLURL: "https://org.github.io/repo/", Status: "built", BuildType: "legacy", Source: &git.PagesSource{Branch: "main", Path: "/"}},
+			&v1alpha1.RepositoryPagesStatus{URL: "https://org.github.io/repo/", BuildStatus: "built"},
+		},
+		{
+			"disables a removed site",
+			nil,
+			true,
+			nil,
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			gitClient := git.TestClient()
+			ghrepo := &github.Repository{DefaultBranch: github.String("main")}
+
+			repository := &v1alpha1.Repository{Spec: v1alpha1.RepositorySpec{Organization: "org", Name: "repo", Pages: tt.spec}}
+			if tt.spec == nil {
+				// the site was managed by an earlier reconcile
+				if err := gitClient.CreatePages(ctx, "org", "repo", &git.Pages{BuildType: "legacy", Source: &git.PagesSource{Branch: "main", Path: "/"}}); err != nil {
+					t.Fatal(err)
+				}
+				pages, _ := gitClient.GetPages(ctx, "org", "repo")
+				pages.Status = "built"
+				repository.Status.Pages = &v1alpha1.RepositoryPagesStatus{URL: pages.HTMLURL, BuildStatus: pages.Status}
+			}
+
+			if err := testRepositoryReconciler(gitClient, tt.actualDelete).reconcilePages(ctx, repository, ghrepo); err != nil {
+				t.Fatal(err)
+			}
+
+			pages, err := gitClient.GetPages(ctx, "org", "repo")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(pages, tt.expectedPages) {
+				t.Errorf("expected pages %+v, got %+v", tt.expectedPages, pages)
+			}
+			if !reflect.DeepEqual(repository.Status.Pages, tt.expectedStatus) {
+				t.Errorf("expected status %+v, got %+v", tt.expectedStatus, repository.Status.Pages)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/policy"
+)
+
+const (
+	policyViolationReason = "PolicyViolation"
+	policyAllowedReason   = "PolicyAllowed"
+)
+
+// checkPolicy reports whether the organization policies allow the repository
+// and reflects it in the Allowed condition, repositories that were never
+// denied don't get the condition. A denied repository that is deleted is
+// released without deleting it on GitHub.
+func (r *RepositoryReconciler) checkPolicy(ctx context.Context, repository *v1alpha1.Repository) (bool, error) {
+	err := policy.Check(ctx, r.Client, repository)
+	violation, denied := err.(*policy.Violation)
+	if err != nil && !denied {
+		return false, err
+	}
+
+	if denied && !repository.ObjectMeta.DeletionTimestamp.IsZero() &&
+		containsString(repository.GetFinalizers(), repoFinalizerName) {
+		r.Recorder.Eventf(repository, corev1.EventTypeWarning, "DeleteSkipped", "released repository without deleting it: %s", violation.Reason)
+		repository.ObjectMeta.Finalizers = removeString(repository.ObjectMeta.Finalizers, repoFinalizerName)
+		return false, r.Client.Update(ctx, repository)
+	}
+
+	current := v1alpha1.FindCondition(repository.Status.Conditions, v1alpha1.AllowedCondition)
+	switch {
+	case denied && (current == nil || current.Status != corev1.ConditionFalse || current.Message != violation.Reason):
+		v1alpha1.SetCondition(&repository.Status.Conditions, v1alpha1.Condition{
+			Type:    v1alpha1.AllowedCondition,
+			Status:  corev1.ConditionFalse,
+			Reason:  policyViolationReason,
+			Message: violation.Reason,
+		})
+		r.Recorder.Event(repository, corev1.EventTypeWarning, policyViolationReason, violation.Reason)
+	case !denied && current != nil && current.Status != corev1.ConditionTrue:
+		v1alpha1.SetCondition(&repository.Status.Conditions, v1alpha1.Condition{
+			Type:    v1alpha1.AllowedCondition,
+			Status:  corev1.ConditionTrue,
+			Reason:  policyAllowedReason,
+			Message: "the organization policies allow the repository",
+		})
+		r.Recorder.Event(repository, corev1.EventTypeNormal, policyAllowedReason, "the organization policies allow the repository")
+	default:
+		return !denied, nil
+	}
+
+	return !denied, r.updateRepositoryConditions(ctx, repository)
+}
+
+// policyViolation splits the result of a policy check into the reason the
+// policies deny the object and any other error
+func policyViolation(err error) (string, error) {
+	if violation, ok := err.(*policy.Violation); ok {
+		return violation.Reason, nil
+	}
+	return "", err
+}
+
+// releasedByPolicy reports whether the organization policies no longer let the
+// namespace manage the organization, objects being deleted are then released
+// without deleting what they manage on GitHub
+func releasedByPolicy(ctx context.Context, reader client.Reader, recorder record.EventRecorder, obj runtime.Object, namespace, org string) (bool, error) {
+	reason, err := policyViolation(policy.CheckOrganization(ctx, reader, namespace, org))
+	if err != nil || reason == "" {
+		return false, err
+	}
+	recorder.Eventf(obj, corev1.EventTypeWarning, "DeleteSkipped", "released without deleting it on GitHub: %s", reason)
+	return true, nil
+}
+
+// policyToRepositories queues every Repository, a policy can allow or deny
+// repositories of any organization
+func (r *RepositoryReconciler) policyToRepositories(obj handler.MapObject) []reconcile.Request {
+	var list v1alpha1.RepositoryList
+	if err := r.Client.List(context.Background(), &list); err != nil {
+		r.Log.Error(err, "unable to list repositories", "organizationPolicy", obj.Meta.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, repository := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: repository.Namespace, Name: repository.Name},
+		})
+	}
+	return requests
+}
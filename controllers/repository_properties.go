@@ -0,0 +1,91 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// reconcileCustomProperties sets the custom property values in the spec and
+// unsets the ones that were managed before but are no longer in the spec
+func (r *RepositoryReconciler) reconcileCustomProperties(ctx context.Context, repository *v1alpha1.Repository) error {
+	if len(repository.Spec.CustomProperties) == 0 && len(repository.Status.ManagedCustomProperties) == 0 {
+		return nil
+	}
+
+	org, name := repository.Spec.Organization, repository.GitHubName()
+	log := r.Log.WithValues("repository", org+"/"+name)
+
+	existing, err := r.GitClient.GetCustomPropertyValues(ctx, org, name)
+	if err != nil {
+		return err
+	}
+
+	observed := map[string]v1alpha1.RepositoryCustomProperty{}
+	for _, property := range existing {
+		observed[property.Name] = property
+	}
+
+	changes := []v1alpha1.RepositoryCustomProperty{}
+	desired := map[string]bool{}
+	managed := []string{}
+	for _, property := range repository.Spec.CustomProperties {
+		desired[property.Name] = true
+		managed = append(managed, property.Name)
+
+		if current, ok := observed[property.Name]; !ok || !propertyMatches(current, property) {
+			changes = append(changes, property)
+		}
+	}
+
+	for _, property := range repository.Status.ManagedCustomProperties {
+		if _, ok := observed[property]; ok && !desired[property] {
+			changes = append(changes, v1alpha1.RepositoryCustomProperty{Name: property})
+		}
+	}
+
+	if len(changes) > 0 {
+		log.Info("updating custom properties", "count", len(changes))
+		if err := r.GitClient.SetCustomPropertyValues(ctx, org, name, changes); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Updated", "updated %d custom properties", len(changes))
+		driftCorrections.WithLabelValues("Repository", "customProperties").Inc()
+	}
+
+	repository.Status.ManagedCustomProperties = managed
+	return nil
+}
+
+// propertyMatches reports whether the observed value equals the desired one,
+// multi_select values are compared regardless of their order
+func propertyMatches(current, desired v1alpha1.RepositoryCustomProperty) bool {
+	if current.Value != desired.Value {
+		return false
+	}
+
+	currentValues := append([]string{}, current.Values...)
+	desiredValues := append([]string{}, desired.Values...)
+	sort.Strings(currentValues)
+	sort.Strings(desiredValues)
+	return strings.Join(currentValues, ",") == strings.Join(desiredValues, ",")
+}
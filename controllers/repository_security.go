@@ -0,0 +1,76 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// reconcileSecurity enables or disables the security and analysis features
+// set in the spec and records the observed features in the status
+func (r *RepositoryReconciler) reconcileSecurity(ctx context.Context, repository *v1alpha1.Repository) error {
+	desired := repository.Spec.Security
+	if desired == nil {
+		repository.Status.Security = nil
+		return nil
+	}
+
+	org, name := repository.Spec.Organization, repository.GitHubName()
+	log := r.Log.WithValues("repository", org+"/"+name)
+
+	observed, err := r.GitClient.GetSecurity(ctx, org, name)
+	if err != nil {
+		return err
+	}
+
+	// only send the features that drifted
+	changes := &v1alpha1.RepositorySecurity{
+		DependabotAlerts:              securityChange(desired.DependabotAlerts, observed.DependabotAlerts),
+		DependabotSecurityUpdates:     securityChange(desired.DependabotSecurityUpdates, observed.DependabotSecurityUpdates),
+		SecretScanning:                securityChange(desired.SecretScanning, observed.SecretScanning),
+		SecretScanningPushProtection:  securityChange(desired.SecretScanningPushProtection, observed.SecretScanningPushProtection),
+		PrivateVulnerabilityReporting: securityChange(desired.PrivateVulnerabilityReporting, observed.PrivateVulnerabilityReporting),
+	}
+
+	if *changes != (v1alpha1.RepositorySecurity{}) {
+		log.Info("updating security and analysis features")
+		if err := r.GitClient.UpdateSecurity(ctx, org, name, changes); err != nil {
+			return err
+		}
+		r.Recorder.Event(repository, corev1.EventTypeNormal, "Updated", "updated security and analysis features")
+		driftCorrections.WithLabelValues("Repository", "security").Inc()
+
+		// features can be refused by organization policies, report what GitHub applied
+		if observed, err = r.GitClient.GetSecurity(ctx, org, name); err != nil {
+			return err
+		}
+	}
+
+	repository.Status.Security = observed
+	return nil
+}
+
+// securityChange returns desired when it differs from the observed enablement
+func securityChange(desired *bool, observed bool) *bool {
+	if desired == nil || *desired == observed {
+		return nil
+	}
+	return desired
+}
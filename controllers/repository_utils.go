@@ -20,11 +20,15 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/go-github/v28/github"
 	"go.hein.dev/github-controller/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (r *RepositoryReconciler) addFinalizer(ctx context.Context, repository *v1alpha1.Repository) error {
@@ -37,16 +41,25 @@ func (r *RepositoryReconciler) addFinalizer(ctx context.Context, repository *v1a
 }
 
 func (r *RepositoryReconciler) handleDeletion(ctx context.Context, repository *v1alpha1.Repository) error {
-	_, resp, err := r.GitClient.GetRepo(ctx, repository.Spec.Organization, repository.Name)
+	// prefer where the repository was last synced, the spec may point elsewhere
+	org, name := repository.Spec.Organization, repository.GitHubName()
+	if repository.Status.GitHubOrganization != "" && repository.Status.GitHubName != "" {
+		org, name = repository.Status.GitHubOrganization, repository.Status.GitHubName
+	}
+
+	_, resp, err := r.GitClient.GetRepo(ctx, org, name)
 	if err != nil && !isNotFound(resp) {
 		return err
 	}
 
 	if r.ActualDelete {
-		r.Log.Info("actual delete true", "deleting", fmt.Sprintf("%s/%s", repository.Spec.Organization, repository.Name))
-		if err := r.GitClient.DeleteRepo(ctx, repository.Spec.Organization, repository.Name); err != nil {
+		r.Log.Info("actual delete true", "deleting", fmt.Sprintf("%s/%s", org, name))
+		if err := r.GitClient.DeleteRepo(ctx, org, name); err != nil {
 			return err
 		}
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Deleted", "deleted repository %s/%s", org, name)
+	} else {
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "DeleteSkipped", "kept repository %s/%s, --actual-delete is off", org, name)
 	}
 
 	repository.ObjectMeta.Finalizers = removeString(repository.ObjectMeta.Finalizers, repoFinalizerName)
@@ -56,6 +69,37 @@ func (r *RepositoryReconciler) handleDeletion(ctx context.Context, repository *v
 	return nil
 }
 
+// relocateRepository transfers and renames ghrepo to the organization and name
+// in the spec, it reports true when the repository was moved
+func (r *RepositoryReconciler) relocateRepository(ctx context.Context, repository *v1alpha1.Repository, ghrepo *github.Repository) (bool, error) {
+	org, name := repository.Spec.Organization, repository.GitHubName()
+	owner := ghrepo.GetOwner().GetLogin()
+	log := r.Log.WithValues("repository", fmt.Sprintf("%s/%s", org, name))
+
+	// transfers are asynchronous, rename only after the repository arrived
+	if owner != "" && !strings.EqualFold(owner, org) {
+		log.Info("transferring repository", "from", fmt.Sprintf("%s/%s", owner, ghrepo.GetName()))
+		if err := r.GitClient.TransferRepo(ctx, owner, ghrepo.GetName(), org); err != nil {
+			return true, err
+		}
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Transferred", "requested transfer from %s/%s to %s", owner, ghrepo.GetName(), org)
+		driftCorrections.WithLabelValues("Repository", "location").Inc()
+		return true, nil
+	}
+
+	if ghrepo.GetName() != "" && ghrepo.GetName() != name {
+		log.Info("renaming repository", "from", fmt.Sprintf("%s/%s", owner, ghrepo.GetName()))
+		if _, err := r.GitClient.EditRepo(ctx, owner, ghrepo.GetName(), &github.Repository{Name: &name}); err != nil {
+			return true, err
+		}
+		r.Recorder.Eventf(repository, corev1.EventTypeNormal, "Renamed", "renamed from %s to %s", ghrepo.GetName(), name)
+		driftCorrections.WithLabelValues("Repository", "location").Inc()
+		return true, nil
+	}
+
+	return false, nil
+}
+
 func (r *RepositoryReconciler) updateRepositoryStatusDetails(ctx context.Context, ghrepo *github.Repository, repository *v1alpha1.Repository) error {
 	nsn := types.NamespacedName{Namespace: repository.Namespace, Name: repository.Name}
 
@@ -65,14 +109,19 @@ func (r *RepositoryReconciler) updateRepositoryStatusDetails(ctx context.Context
 			return err
 		}
 
+		// the reconcile mutates repository.Status with what it observed and manages
 		repoCopy := repo.DeepCopy()
-		repoCopy.Status = v1alpha1.RepositoryStatus{
-			Status:          v1alpha1.SyncedStatus,
-			URL:             fmt.Sprintf("https://github.com/%s/%s", repository.Spec.Organization, repository.Name),
-			ForkCount:       ghrepo.GetForksCount(),
-			StargazersCount: ghrepo.GetStargazersCount(),
-			WatchersCount:   ghrepo.GetWatchersCount(),
-		}
+		repoCopy.Status = *repository.Status.DeepCopy()
+		repoCopy.Status.Status = v1alpha1.SyncedStatus
+		repoCopy.Status.URL = fmt.Sprintf("https://github.com/%s/%s", repository.Spec.Organization, repository.GitHubName())
+		repoCopy.Status.ForkCount = ghrepo.GetForksCount()
+		repoCopy.Status.StargazersCount = ghrepo.GetStargazersCount()
+		repoCopy.Status.WatchersCount = ghrepo.GetWatchersCount()
+		repoCopy.Status.DefaultBranch = ghrepo.GetDefaultBranch()
+		repoCopy.Status.GitHubID = ghrepo.GetID()
+		repoCopy.Status.GitHubNodeID = ghrepo.GetNodeID()
+		repoCopy.Status.GitHubName = ghrepo.GetName()
+		repoCopy.Status.GitHubOrganization = ghrepo.GetOwner().GetLogin()
 
 		return r.Client.Status().Update(ctx, repoCopy)
 	}); err != nil {
@@ -81,6 +130,29 @@ func (r *RepositoryReconciler) updateRepositoryStatusDetails(ctx context.Context
 	return nil
 }
 
+// updateRepositoryInitStatus records the spec.init options the repository was
+// just created with
+func (r *RepositoryReconciler) updateRepositoryInitStatus(ctx context.Context, repository *v1alpha1.Repository) error {
+	nsn := types.NamespacedName{Namespace: repository.Namespace, Name: repository.Name}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var repo v1alpha1.Repository
+		if err := r.Client.Get(ctx, nsn, &repo); err != nil {
+			return err
+		}
+
+		repoCopy := repo.DeepCopy()
+		repoCopy.Status.Init = &v1alpha1.RepositoryInitStatus{
+			AutoInit:          repository.Spec.Init.AutoInit,
+			GitignoreTemplate: repository.Spec.Init.GitignoreTemplate,
+			LicenseTemplate:   repository.Spec.Init.LicenseTemplate,
+			DefaultBranch:     repository.Spec.Init.DefaultBranch,
+		}
+
+		return r.Client.Status().Update(ctx, repoCopy)
+	})
+}
+
 func (r *RepositoryReconciler) updateRepositoryStatus(ctx context.Context, repository *v1alpha1.Repository, status v1alpha1.StatusReason) error {
 	nsn := types.NamespacedName{Namespace: repository.Namespace, Name: repository.Name}
 
@@ -91,11 +163,29 @@ func (r *RepositoryReconciler) updateRepositoryStatus(ctx context.Context, repos
 		}
 
 		repoCopy := repo.DeepCopy()
-		repoCopy.Status = v1alpha1.RepositoryStatus{
-			Status: status,
-			URL:    fmt.Sprintf("https://github.com/%s/%s", repository.Spec.Organization, repository.Name),
+		repoCopy.Status.Status = status
+		repoCopy.Status.URL = fmt.Sprintf("https://github.com/%s/%s", repository.Spec.Organization, repository.GitHubName())
+
+		return r.Client.Status().Update(ctx, repoCopy)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// updateRepositoryConditions stores the conditions of repository
+func (r *RepositoryReconciler) updateRepositoryConditions(ctx context.Context, repository *v1alpha1.Repository) error {
+	nsn := types.NamespacedName{Namespace: repository.Namespace, Name: repository.Name}
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var repo v1alpha1.Repository
+		if err := r.Client.Get(ctx, nsn, &repo); err != nil {
+			return err
 		}
 
+		repoCopy := repo.DeepCopy()
+		repoCopy.Status.Conditions = repository.Status.Conditions
+
 		return r.Client.Status().Update(ctx, repoCopy)
 	}); err != nil {
 		return err
@@ -103,6 +193,18 @@ func (r *RepositoryReconciler) updateRepositoryStatus(ctx context.Context, repos
 	return nil
 }
 
+// syncedRepository fetches the named Repository and reports whether it is synced with GitHub
+func syncedRepository(ctx context.Context, c client.Client, namespace, name string) (*v1alpha1.Repository, bool, error) {
+	var repository v1alpha1.Repository
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &repository); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return &repository, repository.Status.Status == v1alpha1.SyncedStatus, nil
+}
+
 func isNotFound(r *github.Response) bool {
 	if r != nil && r.StatusCode == http.StatusNotFound {
 		return true
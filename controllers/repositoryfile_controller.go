@@ -0,0 +1,301 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-github/v28/github"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1alpha1 "go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+)
+
+var (
+	repositoryFileFinalizerName = "repositoryfile.finalizers.github.go.hein.dev"
+)
+
+// RepositoryFileReconciler reconciles a RepositoryFile object
+type RepositoryFileReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	GitClient    git.Client
+	ActualDelete bool
+
+	// Recorder records an event for every change made on GitHub
+	Recorder record.EventRecorder
+
+	// Selector optionally limits the reconciled RepositoryFiles to the ones with matching labels
+	Selector labels.Selector
+}
+
+// +kubebuilder:rbac:groups=github.go.hein.dev,resources=repositoryfiles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=github.go.hein.dev,resources=repositoryfiles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=github.go.hein.dev,resources=repositories,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=github.go.hein.dev,resources=organizationpolicies,verbs=get;list;watch
+
+// Reconcile is responsible for reconciling the request
+func (r *RepositoryFileReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	var requeueAfter = 2 * time.Second
+	ctx, plan := git.WithPlan(context.Background())
+	log := r.Log.WithValues("repositoryfile", req.NamespacedName)
+
+	var file v1alpha1.RepositoryFile
+	if err := r.Client.Get(ctx, req.NamespacedName, &file); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !selected(r.Selector, &file) {
+		return ctrl.Result{}, nil
+	}
+
+	// the changes skipped by the dry-run client are stored whichever way the reconcile ends
+	defer func() {
+		if err := r.updateRepositoryFilePlan(ctx, &file, plan); err != nil {
+			log.Error(err, "unable to update planned changes")
+		}
+	}()
+
+	// handle finalizers before any other reconcile logic can fail
+	if !file.ObjectMeta.DeletionTimestamp.IsZero() &&
+		containsString(file.GetFinalizers(), repositoryFileFinalizerName) {
+		log.Info("handle deletion", "name", file.Name)
+		if err := r.handleDeletion(ctx, &file); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	content, ready, err := r.fileContent(ctx, &file)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !ready {
+		r.updateRepositoryFileStatus(ctx, &file, v1alpha1.WaitingStatus)
+		log.Info("referenced configmap does not exist", "configMap", file.Spec.ContentFrom.ConfigMapKeyRef.Name)
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	repository, ready, err := syncedRepository(ctx, r.Client, req.Namespace, file.Spec.RepositoryRef)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
+	if !ready {
+		r.updateRepositoryFileStatus(ctx, &file, v1alpha1.WaitingStatus)
+		log.Info("referenced repository not yet synced", "repository", file.Spec.RepositoryRef)
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	// repositories the organization policies don't allow are left alone on GitHub
+	allowed, policyErr := r.checkPolicy(ctx, &file, repository)
+	if policyErr != nil {
+		return ctrl.Result{}, policyErr
+	}
+	if !allowed {
+		log.Info("repository file not allowed by organization policies", "repository", file.Spec.RepositoryRef)
+		return ctrl.Result{}, nil
+	}
+
+	org, repoName := repository.Spec.Organization, repository.GitHubName()
+	branch := file.Spec.Branch
+	if branch == "" {
+		branch = repository.Status.DefaultBranch
+	}
+	log = log.WithValues("repository", org+"/"+repoName, "branch", branch, "path", file.Spec.Path)
+
+	// content and repository are both ready, add finalizer for github-Delete before committing,
+	// a file that already has the content is managed and deleted just the same
+	if file.ObjectMeta.DeletionTimestamp.IsZero() &&
+		!containsString(file.GetFinalizers(), repositoryFileFinalizerName) {
+		log.Info("adding finalizer", "name", file.Name)
+		if err := r.addFinalizer(ctx, &file); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// GitHub reports the blob SHA of files, equal SHAs mean equal content
+	sha := git.BlobSHA(content)
+	current, err := r.fileSHA(ctx, org, repoName, file.Spec.Path, branch)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
+	if current == sha {
+		if err := r.updateRepositoryFileStatusDetails(ctx, &file, v1alpha1.SyncedStatus, org, repoName, branch, sha, ""); err != nil {
+			return ctrl.Result{RequeueAfter: requeueAfter}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	r.updateRepositoryFileStatus(ctx, &file, v1alpha1.UpdatingStatus)
+
+	target := branch
+	if file.Spec.PullRequestOnProtectedBranch {
+		ghBranch, _, err := r.GitClient.GetBranch(ctx, org, repoName, branch)
+		if err != nil {
+			return ctrl.Result{RequeueAfter: requeueAfter}, err
+		}
+		if ghBranch.GetProtected() {
+			target = pullRequestBranch(&file)
+			if err := r.ensureBranch(ctx, org, repoName, target, ghBranch.GetCommit().GetSHA()); err != nil {
+				return ctrl.Result{RequeueAfter: requeueAfter}, err
+			}
+			if current, err = r.fileSHA(ctx, org, repoName, file.Spec.Path, target); err != nil {
+				return ctrl.Result{RequeueAfter: requeueAfter}, err
+			}
+		}
+	}
+
+	message := commitMessage(&file)
+	if current != sha {
+		log.Info("committing file", "target", target)
+		opts := &github.RepositoryContentFileOptions{
+			Message: &message,
+			Content: content,
+			Branch:  &target,
+		}
+		if current != "" {
+			opts.SHA = &current
+		}
+		if author := file.Spec.Author; author != nil {
+			opts.Author = &github.CommitAuthor{Name: &author.Name, Email: &author.Email}
+			opts.Committer = opts.Author
+		}
+		if err := r.GitClient.CommitFile(ctx, org, repoName, file.Spec.Path, opts); err != nil {
+			return ctrl.Result{RequeueAfter: requeueAfter}, err
+		}
+		r.Recorder.Eventf(&file, corev1.EventTypeNormal, "Updated", "committed %s to %s/%s on %s", file.Spec.Path, org, repoName, target)
+	}
+
+	// the dry-run client only planned the commit, GitHub still holds the previous
+	// content so it must not be recorded as synced
+	if changes := plan.Changes(); len(changes) > 0 {
+		log.Info("planned file changes", "count", len(changes))
+		return ctrl.Result{}, nil
+	}
+
+	if target == branch {
+		if err := r.updateRepositoryFileStatusDetails(ctx, &file, v1alpha1.SyncedStatus, org, repoName, branch, sha, ""); err != nil {
+			return ctrl.Result{RequeueAfter: requeueAfter}, err
+		}
+		log.Info("committed file")
+		return ctrl.Result{}, nil
+	}
+
+	// the branch is protected, propose the content and wait for the pull request to be merged
+	pull, err := r.GitClient.FindPullRequest(ctx, org, repoName, target, branch)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
+	if pull == nil {
+		log.Info("opening pull request", "head", target)
+		body := fmt.Sprintf("Proposed by the RepositoryFile %s/%s of the github-controller.", file.Namespace, file.Name)
+		if pull, err = r.GitClient.CreatePullRequest(ctx, org, repoName, &github.NewPullRequest{
+			Title: &message,
+			Head:  &target,
+			Base:  &branch,
+			Body:  &body,
+		}); err != nil {
+			return ctrl.Result{RequeueAfter: requeueAfter}, err
+		}
+		r.Recorder.Eventf(&file, corev1.EventTypeNormal, "Created", "opened pull request %s", pull.GetHTMLURL())
+	}
+
+	if err := r.updateRepositoryFileStatusDetails(ctx, &file, v1alpha1.WaitingStatus, org, repoName, branch, sha, pull.GetHTMLURL()); err != nil {
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
+	log.Info("waiting for pull request", "pullRequest", pull.GetHTMLURL())
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager configures the controller
+func (r *RepositoryFileReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("repositoryfile-controller")
+	}
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.RepositoryFile{}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.configMapToRepositoryFiles),
+		}).
+		Watches(&source.Kind{Type: &v1alpha1.OrganizationPolicy{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.policyToRepositoryFiles),
+		})
+
+	if r.Selector != nil {
+		builder = builder.WithEventFilter(selectorPredicate(r.Selector, &v1alpha1.RepositoryFile{}))
+	}
+
+	return builder.Complete(r)
+}
+
+// configMapToRepositoryFiles queues every RepositoryFile sourcing its content from the ConfigMap
+func (r *RepositoryFileReconciler) configMapToRepositoryFiles(obj handler.MapObject) []reconcile.Request {
+	var list v1alpha1.RepositoryFileList
+	if err := r.Client.List(context.Background(), &list, client.InNamespace(obj.Meta.GetNamespace())); err != nil {
+		r.Log.Error(err, "unable to list repository files", "namespace", obj.Meta.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, file := range list.Items {
+		if from := file.Spec.ContentFrom; from != nil && from.ConfigMapKeyRef.Name == obj.Meta.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: file.Namespace, Name: file.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// policyToRepositoryFiles queues every RepositoryFile, a policy can allow or
+// deny the repository of any of them
+func (r *RepositoryFileReconciler) policyToRepositoryFiles(obj handler.MapObject) []reconcile.Request {
+	var list v1alpha1.RepositoryFileList
+	if err := r.Client.List(context.Background(), &list); err != nil {
+		r.Log.Error(err, "unable to list repository files", "organizationPolicy", obj.Meta.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, file := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: file.Namespace, Name: file.Name},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/git"
+)
+
+var _ = Describe("Run RepositoryFile Controller", func() {
+	const timeout = time.Second * 10
+	const interval = time.Millisecond * 500
+
+	Context("Run a RepositoryFile without its Repository", func() {
+		It("Should wait without a finalizer", func() {
+			key := types.NamespacedName{Name: "test-waiting-file", Namespace: "default"}
+			file := &v1alpha1.RepositoryFile{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: v1alpha1.RepositoryFileSpec{
+					RepositoryRef: "missing-repo",
+					Path:          "README.md",
+					Content:       "hello",
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), file)).Should(Succeed())
+
+			By("Describing Waiting Status")
+			Eventually(func() bool {
+				f := &v1alpha1.RepositoryFile{}
+				k8sClient.Get(context.Background(), key, f)
+				return f.Status.Status == v1alpha1.WaitingStatus && len(f.GetFinalizers()) == 0
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(k8sClient.Delete(context.Background(), file)).Should(Succeed())
+			Eventually(func() bool {
+				return isGone(key, &v1alpha1.RepositoryFile{})
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+
+	Context("Run a new RepositoryFile", func() {
+		It("Should commit and delete the file", func() {
+			repokey := types.NamespacedName{Name: "test-file-repo", Namespace: "default"}
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: repokey.Name, Namespace: repokey.Namespace},
+				Spec: v1alpha1.RepositorySpec{
+					Organization: "awsctrl",
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), repo)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), repo)).Should(Succeed())
+			}()
+
+			key := types.NamespacedName{Name: "test-file", Namespace: "default"}
+			file := &v1alpha1.RepositoryFile{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: v1alpha1.RepositoryFileSpec{
+					RepositoryRef: repokey.Name,
+					Path:          "README.md",
+					Branch:        "main",
+					Content:       "hello",
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), file)).Should(Succeed())
+
+			By("Describing RepositoryFile Finalizers")
+			Eventually(func() bool {
+				f := &v1alpha1.RepositoryFile{}
+				k8sClient.Get(context.Background(), key, f)
+				return len(f.GetFinalizers()) == 1
+			}, timeout, interval).Should(BeTrue())
+
+			By("Describing Synced Status")
+			Eventually(func() bool {
+				f := &v1alpha1.RepositoryFile{}
+				k8sClient.Get(context.Background(), key, f)
+				return f.Status.Status == v1alpha1.SyncedStatus && f.Status.SHA == git.BlobSHA([]byte("hello"))
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() bool {
+				return hasEvent(key.Namespace, key.Name, "Updated")
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(k8sClient.Delete(context.Background(), file)).Should(Succeed())
+
+			By("Describing deletion state")
+			Eventually(func() bool {
+				return isGone(key, &v1alpha1.RepositoryFile{})
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() bool {
+				return hasEvent(key.Namespace, key.Name, "Deleted")
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+})
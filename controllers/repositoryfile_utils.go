@@ -0,0 +1,224 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v28/github"
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/policy"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+func (r *RepositoryFileReconciler) addFinalizer(ctx context.Context, file *v1alpha1.RepositoryFile) error {
+	file.ObjectMeta.Finalizers = append(file.ObjectMeta.Finalizers, repositoryFileFinalizerName)
+	if err := r.Client.Update(ctx, file); err != nil {
+		return err
+	}
+
+	return r.updateRepositoryFileStatus(ctx, file, v1alpha1.CreatingStatus)
+}
+
+func (r *RepositoryFileReconciler) handleDeletion(ctx context.Context, file *v1alpha1.RepositoryFile) error {
+	org := file.Status.GitHubOrganization
+	repo := file.Status.GitHubRepository
+	branch := file.Status.Branch
+
+	deleteFile := org != "" && r.ActualDelete
+	if deleteFile {
+		released, err := releasedByPolicy(ctx, r.Client, r.Recorder, file, file.Namespace, org)
+		if err != nil {
+			return err
+		}
+		deleteFile = !released
+	}
+
+	if deleteFile {
+		current, err := r.fileSHA(ctx, org, repo, file.Spec.Path, branch)
+		if err != nil {
+			return err
+		}
+		if current != "" {
+			r.Log.Info("actual delete true", "deleting", fmt.Sprintf("%s/%s/%s", org, repo, file.Spec.Path))
+			message := fmt.Sprintf("Remove %s", file.Spec.Path)
+			if err := r.GitClient.DeleteFile(ctx, org, repo, file.Spec.Path, &github.RepositoryContentFileOptions{
+				Message: &message,
+				SHA:     &current,
+				Branch:  &branch,
+			}); err != nil {
+				return err
+			}
+			r.Recorder.Eventf(file, corev1.EventTypeNormal, "Deleted", "deleted %s from %s/%s on %s", file.Spec.Path, org, repo, branch)
+		}
+	}
+
+	file.ObjectMeta.Finalizers = removeString(file.ObjectMeta.Finalizers, repositoryFileFinalizerName)
+	if err := r.Client.Update(context.Background(), file); err != nil {
+		return err
+	}
+	return nil
+}
+
+// fileContent returns the desired content of the file, it reports false when
+// the referenced ConfigMap does not exist yet
+func (r *RepositoryFileReconciler) fileContent(ctx context.Context, file *v1alpha1.RepositoryFile) ([]byte, bool, error) {
+	from := file.Spec.ContentFrom
+	if from == nil {
+		return []byte(file.Spec.Content), true, nil
+	}
+
+	var configMap corev1.ConfigMap
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: from.ConfigMapKeyRef.Name, Namespace: file.Namespace}, &configMap); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	if value, ok := configMap.Data[from.ConfigMapKeyRef.Key]; ok {
+		return []byte(value), true, nil
+	}
+	if value, ok := configMap.BinaryData[from.ConfigMapKeyRef.Key]; ok {
+		return value, true, nil
+	}
+	return nil, false, fmt.Errorf("referenced ConfigMap %q does not contain key %q", configMap.Name, from.ConfigMapKeyRef.Key)
+}
+
+// fileSHA returns the blob SHA of the file on the branch, empty when the file does not exist
+func (r *RepositoryFileReconciler) fileSHA(ctx context.Context, org, repo, path, branch string) (string, error) {
+	current, resp, err := r.GitClient.GetFile(ctx, org, repo, path, branch)
+	if err != nil {
+		if isNotFound(resp) {
+			return "", nil
+		}
+		return "", err
+	}
+	return current.GetSHA(), nil
+}
+
+// ensureBranch creates the branch from the commit SHA unless it already exists
+func (r *RepositoryFileReconciler) ensureBranch(ctx context.Context, org, repo, branch, sha string) error {
+	_, resp, err := r.GitClient.GetBranch(ctx, org, repo, branch)
+	if err == nil {
+		return nil
+	}
+	if !isNotFound(resp) {
+		return err
+	}
+
+	r.Log.Info("creating branch", "repository", org+"/"+repo, "branch", branch)
+	return r.GitClient.CreateBranch(ctx, org, repo, branch, sha)
+}
+
+func (r *RepositoryFileReconciler) updateRepositoryFileStatusDetails(ctx context.Context, file *v1alpha1.RepositoryFile, status v1alpha1.StatusReason, org, repo, branch, sha, pullRequestURL string) error {
+	nsn := types.NamespacedName{Namespace: file.Namespace, Name: file.Name}
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var file v1alpha1.RepositoryFile
+		if err := r.Client.Get(ctx, nsn, &file); err != nil {
+			return err
+		}
+
+		fileCopy := file.DeepCopy()
+		fileCopy.Status = v1alpha1.RepositoryFileStatus{
+			Status:             status,
+			SHA:                sha,
+			PullRequestURL:     pullRequestURL,
+			GitHubRepository:   repo,
+			GitHubOrganization: org,
+			Branch:             branch,
+		}
+
+		return r.Client.Status().Update(ctx, fileCopy)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkPolicy reports whether the organization policies allow the repository
+// the file is committed to, denied RepositoryFiles get the Denied status with
+// the reason
+func (r *RepositoryFileReconciler) checkPolicy(ctx context.Context, file *v1alpha1.RepositoryFile, repository *v1alpha1.Repository) (bool, error) {
+	reason, err := policyViolation(policy.Check(ctx, r.Client, repository))
+	if err != nil || reason == "" {
+		return err == nil, err
+	}
+
+	if file.Status.Status != v1alpha1.DeniedStatus || file.Status.Message != reason {
+		r.Recorder.Event(file, corev1.EventTypeWarning, policyViolationReason, reason)
+	}
+
+	nsn := types.NamespacedName{Namespace: file.Namespace, Name: file.Name}
+	return false, retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var file v1alpha1.RepositoryFile
+		if err := r.Client.Get(ctx, nsn, &file); err != nil {
+			return err
+		}
+
+		if file.Status.Status == v1alpha1.DeniedStatus && file.Status.Message == reason {
+			return nil // no need to update
+		}
+
+		fileCopy := file.DeepCopy()
+		fileCopy.Status.Status = v1alpha1.DeniedStatus
+		fileCopy.Status.Message = reason
+
+		return r.Client.Status().Update(ctx, fileCopy)
+	})
+}
+
+func (r *RepositoryFileReconciler) updateRepositoryFileStatus(ctx context.Context, file *v1alpha1.RepositoryFile, status v1alpha1.StatusReason) error {
+	nsn := types.NamespacedName{Namespace: file.Namespace, Name: file.Name}
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var file v1alpha1.RepositoryFile
+		if err := r.Client.Get(ctx, nsn, &file); err != nil {
+			return err
+		}
+
+		if file.Status.Status == status {
+			return nil // no need to update
+		}
+
+		fileCopy := file.DeepCopy()
+		fileCopy.Status.Status = status
+		fileCopy.Status.Message = ""
+
+		return r.Client.Status().Update(ctx, fileCopy)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// pullRequestBranch is the branch proposing the content of the file
+func pullRequestBranch(file *v1alpha1.RepositoryFile) string {
+	return fmt.Sprintf("github-controller/%s/%s", file.Namespace, file.Name)
+}
+
+func commitMessage(file *v1alpha1.RepositoryFile) string {
+	if file.Spec.CommitMessage != "" {
+		return file.Spec.CommitMessage
+	}
+	return fmt.Sprintf("Update %s", file.Spec.Path)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// selected reports whether the object matches the selector, a nil selector
+// selects everything
+func selected(selector labels.Selector, obj metav1.Object) bool {
+	return selector == nil || selector.Matches(labels.Set(obj.GetLabels()))
+}
+
+// selectorPredicate drops the events of the objects of the kind that don't
+// match the selector, the events of other kinds like owned Secrets or
+// OrganizationPolicies pass through
+func selectorPredicate(selector labels.Selector, kind runtime.Object) predicate.Funcs {
+	selects := func(meta metav1.Object, obj runtime.Object) bool {
+		return reflect.TypeOf(obj) != reflect.TypeOf(kind) || selected(selector, meta)
+	}
+
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return selects(e.Meta, e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return selects(e.MetaNew, e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return selects(e.Meta, e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return selects(e.Meta, e.Object)
+		},
+	}
+}
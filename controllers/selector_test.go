@@ -0,0 +1,80 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+)
+
+func TestSelected(t *testing.T) {
+	selector := labels.SelectorFromSet(labels.Set{"team": "a"})
+	matching := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "a"}}}
+	other := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "b"}}}
+
+	if !selected(nil, other) {
+		t.Error("expected a nil selector to select everything")
+	}
+	if !selected(selector, matching) {
+		t.Error("expected the matching repository to be selected")
+	}
+	if selected(selector, other) {
+		t.Error("expected the other repository not to be selected")
+	}
+}
+
+func TestSelectorPredicate(t *testing.T) {
+	predicate := selectorPredicate(labels.SelectorFromSet(labels.Set{"team": "a"}), &v1alpha1.Repository{})
+
+	matching := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "a"}}}
+	other := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "b"}}}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled"}}
+
+	tests := []struct {
+		name     string
+		meta     metav1.Object
+		obj      runtime.Object
+		expected bool
+	}{
+		{"matching", matching, matching, true},
+		{"other", other, other, false},
+		{"other kind", secret, secret, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := map[string]bool{
+				"create":  predicate.Create(event.CreateEvent{Meta: tt.meta, Object: tt.obj}),
+				"update":  predicate.Update(event.UpdateEvent{MetaOld: tt.meta, ObjectOld: tt.obj, MetaNew: tt.meta, ObjectNew: tt.obj}),
+				"delete":  predicate.Delete(event.DeleteEvent{Meta: tt.meta, Object: tt.obj}),
+				"generic": predicate.Generic(event.GenericEvent{Meta: tt.meta, Object: tt.obj}),
+			}
+			for kind, result := range results {
+				if result != tt.expected {
+					t.Errorf("expected the %s event to pass %v, got %v", kind, tt.expected, result)
+				}
+			}
+		})
+	}
+}
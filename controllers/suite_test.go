@@ -17,6 +17,7 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -26,6 +27,10 @@ import (
 
 	"go.hein.dev/github-controller/api/v1alpha1"
 	"go.hein.dev/github-controller/git"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -44,6 +49,9 @@ var k8sClient client.Client
 var k8sManager ctrl.Manager
 var testEnv *envtest.Environment
 
+// ignoredLabel marks the Repositories the label selector of the suite skips
+const ignoredLabel = "test.github.go.hein.dev/ignored"
+
 func TestAPIs(t *testing.T) {
 	RegisterFailHandler(Fail)
 
@@ -87,12 +95,43 @@ var _ = BeforeSuite(func(done Done) {
 
 	gitclient := git.TestClient()
 
+	selector, err := labels.Parse(ignoredLabel + "!=true")
+	Expect(err).ToNot(HaveOccurred())
+
 	err = (&RepositoryReconciler{
 		Client:       k8sManager.GetClient(),
 		Log:          ctrl.Log.WithName("controllers").WithName("Repository"),
 		Scheme:       k8sManager.GetScheme(),
 		GitClient:    gitclient,
 		ActualDelete: true,
+		Selector:     selector,
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&ActionsSecretReconciler{
+		Client:       k8sManager.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("ActionsSecret"),
+		Scheme:       k8sManager.GetScheme(),
+		GitClient:    gitclient,
+		ActualDelete: true,
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&RepositoryFileReconciler{
+		Client:       k8sManager.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("RepositoryFile"),
+		Scheme:       k8sManager.GetScheme(),
+		GitClient:    gitclient,
+		ActualDelete: true,
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&OrganizationCustomPropertyReconciler{
+		Client:       k8sManager.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("OrganizationCustomProperty"),
+		Scheme:       k8sManager.GetScheme(),
+		GitClient:    gitclient,
+		ActualDelete: true,
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -113,3 +152,23 @@ var _ = AfterSuite(func() {
 	err := testEnv.Stop()
 	Expect(err).ToNot(HaveOccurred())
 })
+
+// hasEvent reports whether an event with the reason was recorded for the
+// object, events of cluster scoped objects are in the default namespace
+func hasEvent(namespace, name, reason string) bool {
+	var events corev1.EventList
+	if err := k8sClient.List(context.Background(), &events, client.InNamespace(namespace)); err != nil {
+		return false
+	}
+	for _, event := range events.Items {
+		if event.InvolvedObject.Name == name && event.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// isGone reports whether the object was removed from the API server
+func isGone(key client.ObjectKey, obj runtime.Object) bool {
+	return errors.IsNotFound(k8sClient.Get(context.Background(), key, obj))
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// actionsPublicKey is the key GitHub hands out to encrypt Actions secrets with
+type actionsPublicKey struct {
+	KeyID string `json:"key_id"`
+	Key   string `json:"key"`
+}
+
+// encryptedSecret is the request body for creating or updating an Actions secret
+type encryptedSecret struct {
+	EncryptedValue        string  `json:"encrypted_value"`
+	KeyID                 string  `json:"key_id"`
+	Visibility            string  `json:"visibility,omitempty"`
+	SelectedRepositoryIDs []int64 `json:"selected_repository_ids,omitempty"`
+}
+
+func (in *client) CreateOrUpdateRepoSecret(ctx context.Context, org, repoName, name string, value []byte) error {
+	secret, err := in.encryptedSecret(ctx, fmt.Sprintf("repos/%s/%s/actions/secrets/public-key", org, repoName), value)
+	if err != nil {
+		return err
+	}
+
+	return in.do(ctx, http.MethodPut, fmt.Sprintf("repos/%s/%s/actions/secrets/%s", org, repoName, name), secret, nil)
+}
+
+func (in *client) DeleteRepoSecret(ctx context.Context, org, repoName, name string) error {
+	return in.doDelete(ctx, fmt.Sprintf("repos/%s/%s/actions/secrets/%s", org, repoName, name))
+}
+
+func (in *client) CreateOrUpdateOrgSecret(ctx context.Context, org, name, visibility string, selectedRepositoryIDs []int64, value []byte) error {
+	secret, err := in.encryptedSecret(ctx, fmt.Sprintf("orgs/%s/actions/secrets/public-key", org), value)
+	if err != nil {
+		return err
+	}
+	secret.Visibility = visibility
+	secret.SelectedRepositoryIDs = selectedRepositoryIDs
+
+	return in.do(ctx, http.MethodPut, fmt.Sprintf("orgs/%s/actions/secrets/%s", org, name), secret, nil)
+}
+
+func (in *client) DeleteOrgSecret(ctx context.Context, org, name string) error {
+	return in.doDelete(ctx, fmt.Sprintf("orgs/%s/actions/secrets/%s", org, name))
+}
+
+// encryptedSecret fetches the public key at keyURL and seals value with it
+func (in *client) encryptedSecret(ctx context.Context, keyURL string, value []byte) (*encryptedSecret, error) {
+	var key actionsPublicKey
+	if err := in.do(ctx, http.MethodGet, keyURL, nil, &key); err != nil {
+		return nil, err
+	}
+
+	encrypted, err := encryptSecret(key.Key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedSecret{
+		EncryptedValue: encrypted,
+		KeyID:          key.KeyID,
+	}, nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Autolink is an autolink reference of a repository
+type Autolink struct {
+	ID             int64  `json:"id,omitempty"`
+	KeyPrefix      string `json:"key_prefix"`
+	URLTemplate    string `json:"url_template"`
+	IsAlphanumeric bool   `json:"is_alphanumeric"`
+}
+
+func (in *client) ListAutolinks(ctx context.Context, org, repoName string) ([]*Autolink, error) {
+	var all []*Autolink
+	for page := 1; page != 0; {
+		req, err := in.c.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/autolinks?page=%d", org, repoName, page), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var list []*Autolink
+		resp, err := in.c.Do(ctx, req, &list)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, list...)
+		page = resp.NextPage
+	}
+	return all, nil
+}
+
+func (in *client) CreateAutolink(ctx context.Context, org, repoName string, autolink *Autolink) error {
+	return in.do(ctx, http.MethodPost, fmt.Sprintf("repos/%s/%s/autolinks", org, repoName), autolink, nil)
+}
+
+func (in *client) DeleteAutolink(ctx context.Context, org, repoName string, id int64) error {
+	return in.doDelete(ctx, fmt.Sprintf("repos/%s/%s/autolinks/%d", org, repoName, id))
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/go-github/v28/github"
+)
+
+func (in *client) GetFile(ctx context.Context, org, repoName, path, ref string) (*github.RepositoryContent, *github.Response, error) {
+	file, _, resp, err := in.c.Repositories.GetContents(ctx, org, repoName, path, &github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		return nil, resp, err
+	}
+	if file == nil {
+		return nil, resp, fmt.Errorf("path %q in %s/%s is a directory", path, org, repoName)
+	}
+	return file, resp, nil
+}
+
+func (in *client) CommitFile(ctx context.Context, org, repoName, path string, opts *github.RepositoryContentFileOptions) error {
+	if opts.SHA == nil {
+		_, _, err := in.c.Repositories.CreateFile(ctx, org, repoName, path, opts)
+		return err
+	}
+	_, _, err := in.c.Repositories.UpdateFile(ctx, org, repoName, path, opts)
+	return err
+}
+
+func (in *client) DeleteFile(ctx context.Context, org, repoName, path string, opts *github.RepositoryContentFileOptions) error {
+	_, resp, err := in.c.Repositories.DeleteFile(ctx, org, repoName, path, opts)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			log.Printf("WARNING\t%v", err)
+		} else {
+			return err
+		}
+	}
+	return nil
+}
+
+func (in *client) GetBranch(ctx context.Context, org, repoName, branch string) (*github.Branch, *github.Response, error) {
+	return in.c.Repositories.GetBranch(ctx, org, repoName, branch)
+}
+
+func (in *client) CreateBranch(ctx context.Context, org, repoName, branch, sha string) error {
+	_, _, err := in.c.Git.CreateRef(ctx, org, repoName, &github.Reference{
+		Ref:    github.String("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: github.String(sha)},
+	})
+	return err
+}
+
+func (in *client) FindPullRequest(ctx context.Context, org, repoName, head, base string) (*github.PullRequest, error) {
+	pulls, _, err := in.c.PullRequests.List(ctx, org, repoName, &github.PullRequestListOptions{
+		State: "open",
+		Head:  org + ":" + head,
+		Base:  base,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pulls) == 0 {
+		return nil, nil
+	}
+	return pulls[0], nil
+}
+
+func (in *client) CreatePullRequest(ctx context.Context, org, repoName string, pull *github.NewPullRequest) (*github.PullRequest, error) {
+	created, _, err := in.c.PullRequests.Create(ctx, org, repoName, pull)
+	return created, err
+}
+
+// BlobSHA returns the git blob SHA of content, it matches the SHA GitHub
+// reports for a file with the same content
+func BlobSHA(content []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import "testing"
+
+func TestBlobSHA(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{content: "", expected: "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391"},
+		{content: "hello\n", expected: "ce013625030ba8dba906f756967f9e9ca394464a"},
+	}
+
+	for _, tt := range tests {
+		if sha := BlobSHA([]byte(tt.content)); sha != tt.expected {
+			t.Errorf("expected blob SHA of %q to be %s, got %s", tt.content, tt.expected, sha)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+)
+
+// CustomProperty is the schema of an organization custom property
+type CustomProperty struct {
+	PropertyName  string   `json:"property_name,omitempty"`
+	ValueType     string   `json:"value_type"`
+	Required      bool     `json:"required"`
+	DefaultValue  *string  `json:"default_value"`
+	Description   *string  `json:"description"`
+	AllowedValues []string `json:"allowed_values"`
+}
+
+// customPropertyValue is the value of a custom property of a repository, it
+// is a string, a list of strings for multi_select properties or null when unset
+type customPropertyValue struct {
+	PropertyName string      `json:"property_name"`
+	Value        interface{} `json:"value"`
+}
+
+type customPropertyValues struct {
+	RepositoryNames []string              `json:"repository_names"`
+	Properties      []customPropertyValue `json:"properties"`
+}
+
+func (in *client) GetCustomProperty(ctx context.Context, org, name string) (*CustomProperty, error) {
+	req, err := in.c.NewRequest(http.MethodGet, fmt.Sprintf("orgs/%s/properties/schema/%s", org, name), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var property CustomProperty
+	resp, err := in.c.Do(ctx, req, &property)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &property, nil
+}
+
+func (in *client) CreateOrUpdateCustomProperty(ctx context.Context, org, name string, property *CustomProperty) error {
+	return in.do(ctx, http.MethodPut, fmt.Sprintf("orgs/%s/properties/schema/%s", org, name), property, nil)
+}
+
+func (in *client) DeleteCustomProperty(ctx context.Context, org, name string) error {
+	return in.doDelete(ctx, fmt.Sprintf("orgs/%s/properties/schema/%s", org, name))
+}
+
+func (in *client) GetCustomPropertyValues(ctx context.Context, org, repoName string) ([]v1alpha1.RepositoryCustomProperty, error) {
+	var values []customPropertyValue
+	if err := in.do(ctx, http.MethodGet, fmt.Sprintf("repos/%s/%s/properties/values", org, repoName), nil, &values); err != nil {
+		return nil, err
+	}
+
+	properties := []v1alpha1.RepositoryCustomProperty{}
+	for _, value := range values {
+		property := v1alpha1.RepositoryCustomProperty{Name: value.PropertyName}
+		switch v := value.Value.(type) {
+		case string:
+			property.Value = v
+		case []interface{}:
+			for _, item := range v {
+				property.Values = append(property.Values, fmt.Sprint(item))
+			}
+		default:
+			continue // unset
+		}
+		properties = append(properties, property)
+	}
+	return properties, nil
+}
+
+func (in *client) SetCustomPropertyValues(ctx context.Context, org, repoName string, properties []v1alpha1.RepositoryCustomProperty) error {
+	body := customPropertyValues{RepositoryNames: []string{repoName}}
+	for _, property := range properties {
+		body.Properties = append(body.Properties, customPropertyValue{
+			PropertyName: property.Name,
+			Value:        propertyValue(property),
+		})
+	}
+	return in.do(ctx, http.MethodPatch, fmt.Sprintf("orgs/%s/properties/values", org), body, nil)
+}
+
+// propertyValue converts the property to its API value, a property without
+// values is unset with null
+func propertyValue(property v1alpha1.RepositoryCustomProperty) interface{} {
+	switch {
+	case len(property.Values) > 0:
+		return property.Values
+	case property.Value != "":
+		return property.Value
+	}
+	return nil
+}
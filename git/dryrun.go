@@ -0,0 +1,334 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-github/v28/github"
+	corev1 "k8s.io/api/core/v1"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/mirror"
+)
+
+// Plan collects the changes a dry-run client skipped during a reconcile
+type Plan struct {
+	mu      sync.Mutex
+	changes []string
+}
+
+type planKey struct{}
+
+// WithPlan returns a context that collects the skipped changes into the plan
+func WithPlan(ctx context.Context) (context.Context, *Plan) {
+	plan := &Plan{}
+	return context.WithValue(ctx, planKey{}, plan), plan
+}
+
+// Changes returns the skipped changes in the order they were planned
+func (in *Plan) Changes() []string {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return append([]string(nil), in.changes...)
+}
+
+func (in *Plan) add(change string) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.changes = append(in.changes, change)
+}
+
+// DryRun wraps the client so every read goes to GitHub and every change is
+// logged and added to the plan of the context instead, new methods have to be
+// added here explicitly so they can't mutate GitHub by accident
+func DryRun(next Client, log logr.Logger) Client {
+	return &dryRunClient{next: next, log: log}
+}
+
+type dryRunClient struct {
+	next Client
+	log  logr.Logger
+}
+
+func (in *dryRunClient) plan(ctx context.Context, format string, args ...interface{}) {
+	change := fmt.Sprintf(format, args...)
+	in.log.Info("planned change", "change", change)
+	if plan, ok := ctx.Value(planKey{}).(*Plan); ok {
+		plan.add(change)
+	}
+}
+
+func (in *dryRunClient) GetRepo(ctx context.Context, org, name string) (*github.Repository, *github.Response, error) {
+	return in.next.GetRepo(ctx, org, name)
+}
+
+func (in *dryRunClient) GetRepoByID(ctx context.Context, id int64) (*github.Repository, *github.Response, error) {
+	return in.next.GetRepoByID(ctx, id)
+}
+
+func (in *dryRunClient) CreateRepo(ctx context.Context, org string, repo *v1alpha1.Repository) error {
+	in.plan(ctx, "create repository %s/%s", org, repo.GitHubName())
+	return nil
+}
+
+func (in *dryRunClient) DeleteRepo(ctx context.Context, org, name string) error {
+	in.plan(ctx, "delete repository %s/%s", org, name)
+	return nil
+}
+
+func (in *dryRunClient) EditRepo(ctx context.Context, org, name string, repo *github.Repository) (*github.Repository, error) {
+	in.plan(ctx, "edit repository %s/%s", org, name)
+	return repo, nil
+}
+
+func (in *dryRunClient) TransferRepo(ctx context.Context, org, name, newOwner string) error {
+	in.plan(ctx, "transfer repository %s/%s to %s", org, name, newOwner)
+	return nil
+}
+
+func (in *dryRunClient) RepoInitialized(ctx context.Context, org, name string) (bool, error) {
+	return in.next.RepoInitialized(ctx, org, name)
+}
+
+func (in *dryRunClient) ImportRepo(ctx context.Context, cloneURL, source string, creds *mirror.Credentials) error {
+	in.plan(ctx, "import %s into %s", source, cloneURL)
+	return nil
+}
+
+func (in *dryRunClient) CompareCommits(ctx context.Context, org, name, base, head string) (int, int, error) {
+	return in.next.CompareCommits(ctx, org, name, base, head)
+}
+
+func (in *dryRunClient) BranchExists(ctx context.Context, org, name, branch string) (bool, error) {
+	return in.next.BranchExists(ctx, org, name, branch)
+}
+
+func (in *dryRunClient) RenameBranch(ctx context.Context, org, name, branch, newName string) error {
+	in.plan(ctx, "rename branch %s of %s/%s to %s", branch, org, name, newName)
+	return nil
+}
+
+func (in *dryRunClient) GetKey(ctx context.Context, org, name string, id int64) (*github.Key, *github.Response, error) {
+	return in.next.GetKey(ctx, org, name, id)
+}
+
+func (in *dryRunClient) CreateKey(ctx context.Context, org, name string, key *v1alpha1.Key, secret *corev1.Secret) (*github.Key, error) {
+	ghKey, err := newKey(key, secret)
+	if err != nil {
+		return nil, err
+	}
+	in.plan(ctx, "create deploy key %s in %s/%s", key.Name, org, name)
+	return ghKey, nil
+}
+
+func (in *dryRunClient) DeleteKey(ctx context.Context, org, name string, id int64) error {
+	in.plan(ctx, "delete deploy key %d from %s/%s", id, org, name)
+	return nil
+}
+
+func (in *dryRunClient) CreateOrUpdateRepoSecret(ctx context.Context, org, repoName, name string, value []byte) error {
+	in.plan(ctx, "set actions secret %s in %s/%s", name, org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) DeleteRepoSecret(ctx context.Context, org, repoName, name string) error {
+	in.plan(ctx, "delete actions secret %s from %s/%s", name, org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) CreateOrUpdateOrgSecret(ctx context.Context, org, name, visibility string, selectedRepositoryIDs []int64, value []byte) error {
+	in.plan(ctx, "set actions secret %s in %s", name, org)
+	return nil
+}
+
+func (in *dryRunClient) DeleteOrgSecret(ctx context.Context, org, name string) error {
+	in.plan(ctx, "delete actions secret %s from %s", name, org)
+	return nil
+}
+
+func (in *dryRunClient) ListVariables(ctx context.Context, org, repoName string) ([]*Variable, error) {
+	return in.next.ListVariables(ctx, org, repoName)
+}
+
+func (in *dryRunClient) CreateVariable(ctx context.Context, org, repoName string, variable *Variable) error {
+	in.plan(ctx, "create actions variable %s in %s/%s", variable.Name, org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) UpdateVariable(ctx context.Context, org, repoName string, variable *Variable) error {
+	in.plan(ctx, "update actions variable %s in %s/%s", variable.Name, org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) DeleteVariable(ctx context.Context, org, repoName, name string) error {
+	in.plan(ctx, "delete actions variable %s from %s/%s", name, org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) ListEnvironments(ctx context.Context, org, repoName string) ([]*Environment, error) {
+	return in.next.ListEnvironments(ctx, org, repoName)
+}
+
+func (in *dryRunClient) CreateOrUpdateEnvironment(ctx context.Context, org, repoName string, env *v1alpha1.RepositoryEnvironment) error {
+	in.plan(ctx, "create or update environment %s in %s/%s", env.Name, org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) DeleteEnvironment(ctx context.Context, org, repoName, name string) error {
+	in.plan(ctx, "delete environment %s from %s/%s", name, org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) ListBranchPolicies(ctx context.Context, org, repoName, env string) ([]*BranchPolicy, error) {
+	return in.next.ListBranchPolicies(ctx, org, repoName, env)
+}
+
+func (in *dryRunClient) CreateBranchPolicy(ctx context.Context, org, repoName, env, name string) error {
+	in.plan(ctx, "create deployment branch policy %s for environment %s in %s/%s", name, env, org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) DeleteBranchPolicy(ctx context.Context, org, repoName, env string, id int64) error {
+	in.plan(ctx, "delete deployment branch policy %d from environment %s in %s/%s", id, env, org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) GetSecurity(ctx context.Context, org, repoName string) (*v1alpha1.RepositorySecurityStatus, error) {
+	return in.next.GetSecurity(ctx, org, repoName)
+}
+
+func (in *dryRunClient) UpdateSecurity(ctx context.Context, org, repoName string, security *v1alpha1.RepositorySecurity) error {
+	in.plan(ctx, "update security and analysis features of %s/%s", org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) ListLabels(ctx context.Context, org, repoName string) ([]*github.Label, error) {
+	return in.next.ListLabels(ctx, org, repoName)
+}
+
+func (in *dryRunClient) CreateLabel(ctx context.Context, org, repoName string, label *github.Label) error {
+	in.plan(ctx, "create label %s in %s/%s", label.GetName(), org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) EditLabel(ctx context.Context, org, repoName, name string, label *github.Label) error {
+	in.plan(ctx, "update label %s in %s/%s", name, org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) DeleteLabel(ctx context.Context, org, repoName, name string) error {
+	in.plan(ctx, "delete label %s from %s/%s", name, org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) GetFile(ctx context.Context, org, repoName, path, ref string) (*github.RepositoryContent, *github.Response, error) {
+	return in.next.GetFile(ctx, org, repoName, path, ref)
+}
+
+func (in *dryRunClient) CommitFile(ctx context.Context, org, repoName, path string, opts *github.RepositoryContentFileOptions) error {
+	in.plan(ctx, "commit %s to %s of %s/%s", path, opts.GetBranch(), org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) DeleteFile(ctx context.Context, org, repoName, path string, opts *github.RepositoryContentFileOptions) error {
+	in.plan(ctx, "delete %s from %s of %s/%s", path, opts.GetBranch(), org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) GetBranch(ctx context.Context, org, repoName, branch string) (*github.Branch, *github.Response, error) {
+	return in.next.GetBranch(ctx, org, repoName, branch)
+}
+
+func (in *dryRunClient) CreateBranch(ctx context.Context, org, repoName, branch, sha string) error {
+	in.plan(ctx, "create branch %s in %s/%s", branch, org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) FindPullRequest(ctx context.Context, org, repoName, head, base string) (*github.PullRequest, error) {
+	return in.next.FindPullRequest(ctx, org, repoName, head, base)
+}
+
+func (in *dryRunClient) CreatePullRequest(ctx context.Context, org, repoName string, pull *github.NewPullRequest) (*github.PullRequest, error) {
+	in.plan(ctx, "open pull request from %s to %s in %s/%s", pull.GetHead(), pull.GetBase(), org, repoName)
+	return &github.PullRequest{Title: pull.Title}, nil
+}
+
+func (in *dryRunClient) GetPages(ctx context.Context, org, repoName string) (*Pages, error) {
+	return in.next.GetPages(ctx, org, repoName)
+}
+
+func (in *dryRunClient) CreatePages(ctx context.Context, org, repoName string, pages *Pages) error {
+	in.plan(ctx, "enable pages of %s/%s", org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) UpdatePages(ctx context.Context, org, repoName string, pages *Pages) error {
+	in.plan(ctx, "update pages of %s/%s", org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) DeletePages(ctx context.Context, org, repoName string) error {
+	in.plan(ctx, "disable pages of %s/%s", org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) ListAutolinks(ctx context.Context, org, repoName string) ([]*Autolink, error) {
+	return in.next.ListAutolinks(ctx, org, repoName)
+}
+
+func (in *dryRunClient) CreateAutolink(ctx context.Context, org, repoName string, autolink *Autolink) error {
+	in.plan(ctx, "create autolink %s in %s/%s", autolink.KeyPrefix, org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) DeleteAutolink(ctx context.Context, org, repoName string, id int64) error {
+	in.plan(ctx, "delete autolink %d from %s/%s", id, org, repoName)
+	return nil
+}
+
+func (in *dryRunClient) GetCustomProperty(ctx context.Context, org, name string) (*CustomProperty, error) {
+	return in.next.GetCustomProperty(ctx, org, name)
+}
+
+func (in *dryRunClient) CreateOrUpdateCustomProperty(ctx context.Context, org, name string, property *CustomProperty) error {
+	in.plan(ctx, "create or update custom property %s in %s", name, org)
+	return nil
+}
+
+func (in *dryRunClient) DeleteCustomProperty(ctx context.Context, org, name string) error {
+	in.plan(ctx, "delete custom property %s from %s", name, org)
+	return nil
+}
+
+func (in *dryRunClient) GetCustomPropertyValues(ctx context.Context, org, repoName string) ([]v1alpha1.RepositoryCustomProperty, error) {
+	return in.next.GetCustomPropertyValues(ctx, org, repoName)
+}
+
+func (in *dryRunClient) SetCustomPropertyValues(ctx context.Context, org, repoName string, properties []v1alpha1.RepositoryCustomProperty) error {
+	for _, property := range properties {
+		in.plan(ctx, "set custom property %s of %s/%s", property.Name, org, repoName)
+	}
+	return nil
+}
+
+func (in *dryRunClient) RateLimit(ctx context.Context) (*github.Rate, error) {
+	return in.next.RateLimit(ctx)
+}
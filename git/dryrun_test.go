@@ -0,0 +1,64 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v28/github"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+)
+
+func TestDryRun(t *testing.T) {
+	next := TestClient().(*testclient)
+	cl := DryRun(next, logf.NullLogger{})
+	ctx, plan := WithPlan(context.Background())
+
+	repo := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "app"}}
+	if err := cl.CreateRepo(ctx, "acme", repo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cl.CreateLabel(ctx, "acme", "app", &github.Label{Name: github.String("bug")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next.RepositoryCreated || len(next.Labels) != 0 {
+		t.Fatal("expected the dry-run client not to make any change")
+	}
+
+	// reads still go to GitHub
+	if _, _, err := cl.GetRepo(ctx, "acme", "app"); err == nil {
+		t.Fatal("expected the repository not to exist")
+	}
+
+	expected := []string{"create repository acme/app", "create label bug in acme/app"}
+	if changes := plan.Changes(); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected planned changes %v, got %v", expected, changes)
+	}
+
+	// changes outside of a plan are only logged
+	if err := cl.DeleteRepo(context.Background(), "acme", "app"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan.Changes()) != len(expected) {
+		t.Errorf("expected the plan to be left alone, got %v", plan.Changes())
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+)
+
+// Variable is a GitHub Actions variable
+type Variable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Environment is a GitHub deployment environment
+type Environment struct {
+	ID                     int64                   `json:"id,omitempty"`
+	Name                   string                  `json:"name,omitempty"`
+	ProtectionRules        []*ProtectionRule       `json:"protection_rules,omitempty"`
+	DeploymentBranchPolicy *DeploymentBranchPolicy `json:"deployment_branch_policy,omitempty"`
+}
+
+// ProtectionRule is a single protection rule of an Environment
+type ProtectionRule struct {
+	Type      string                 `json:"type"`
+	WaitTimer int                    `json:"wait_timer,omitempty"`
+	Reviewers []*EnvironmentReviewer `json:"reviewers,omitempty"`
+}
+
+// EnvironmentReviewer is a User or Team that has to approve deployments
+type EnvironmentReviewer struct {
+	Type     string `json:"type"`
+	Reviewer struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login,omitempty"`
+		Slug  string `json:"slug,omitempty"`
+	} `json:"reviewer"`
+}
+
+// DeploymentBranchPolicy restricts which branches can deploy to an Environment
+type DeploymentBranchPolicy struct {
+	ProtectedBranches    bool `json:"protected_branches"`
+	CustomBranchPolicies bool `json:"custom_branch_policies"`
+}
+
+// BranchPolicy is a single branch name pattern allowed to deploy to an Environment
+type BranchPolicy struct {
+	ID   int64  `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+type variables struct {
+	TotalCount int         `json:"total_count"`
+	Variables  []*Variable `json:"variables"`
+}
+
+type environments struct {
+	TotalCount   int            `json:"total_count"`
+	Environments []*Environment `json:"environments"`
+}
+
+type branchPolicies struct {
+	TotalCount     int             `json:"total_count"`
+	BranchPolicies []*BranchPolicy `json:"branch_policies"`
+}
+
+type environmentRequest struct {
+	WaitTimer              int                     `json:"wait_timer"`
+	Reviewers              []environmentReviewerID `json:"reviewers"`
+	DeploymentBranchPolicy *DeploymentBranchPolicy `json:"deployment_branch_policy"`
+}
+
+type environmentReviewerID struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+}
+
+func (in *client) ListVariables(ctx context.Context, org, repoName string) ([]*Variable, error) {
+	var all []*Variable
+	for page := 1; page != 0; {
+		req, err := in.c.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/actions/variables?per_page=30&page=%d", org, repoName, page), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var list variables
+		resp, err := in.c.Do(ctx, req, &list)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, list.Variables...)
+		page = resp.NextPage
+	}
+	return all, nil
+}
+
+func (in *client) CreateVariable(ctx context.Context, org, repoName string, variable *Variable) error {
+	return in.do(ctx, http.MethodPost, fmt.Sprintf("repos/%s/%s/actions/variables", org, repoName), variable, nil)
+}
+
+func (in *client) UpdateVariable(ctx context.Context, org, repoName string, variable *Variable) error {
+	return in.do(ctx, http.MethodPatch, fmt.Sprintf("repos/%s/%s/actions/variables/%s", org, repoName, url.PathEscape(variable.Name)), variable, nil)
+}
+
+func (in *client) DeleteVariable(ctx context.Context, org, repoName, name string) error {
+	return in.doDelete(ctx, fmt.Sprintf("repos/%s/%s/actions/variables/%s", org, repoName, url.PathEscape(name)))
+}
+
+func (in *client) ListEnvironments(ctx context.Context, org, repoName string) ([]*Environment, error) {
+	var all []*Environment
+	for page := 1; page != 0; {
+		req, err := in.c.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/environments?per_page=30&page=%d", org, repoName, page), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var list environments
+		resp, err := in.c.Do(ctx, req, &list)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, list.Environments...)
+		page = resp.NextPage
+	}
+	return all, nil
+}
+
+func (in *client) CreateOrUpdateEnvironment(ctx context.Context, org, repoName string, env *v1alpha1.RepositoryEnvironment) error {
+	body := environmentRequest{
+		WaitTimer: env.WaitTimer,
+		Reviewers: []environmentReviewerID{},
+	}
+
+	for _, reviewer := range env.Reviewers {
+		var id int64
+		switch reviewer.Type {
+		case v1alpha1.TeamReviewer:
+			team, _, err := in.c.Teams.GetTeamBySlug(ctx, org, reviewer.Name)
+			if err != nil {
+				return err
+			}
+			id = team.GetID()
+		default:
+			user, _, err := in.c.Users.Get(ctx, reviewer.Name)
+			if err != nil {
+				return err
+			}
+			id = user.GetID()
+		}
+		body.Reviewers = append(body.Reviewers, environmentReviewerID{Type: string(reviewer.Type), ID: id})
+	}
+
+	if policy := env.DeploymentBranchPolicy; policy != nil {
+		body.DeploymentBranchPolicy = &DeploymentBranchPolicy{
+			ProtectedBranches:    policy.ProtectedBranches,
+			CustomBranchPolicies: !policy.ProtectedBranches,
+		}
+	}
+
+	return in.do(ctx, http.MethodPut, fmt.Sprintf("repos/%s/%s/environments/%s", org, repoName, url.PathEscape(env.Name)), body, nil)
+}
+
+func (in *client) DeleteEnvironment(ctx context.Context, org, repoName, name string) error {
+	return in.doDelete(ctx, fmt.Sprintf("repos/%s/%s/environments/%s", org, repoName, url.PathEscape(name)))
+}
+
+func (in *client) ListBranchPolicies(ctx context.Context, org, repoName, env string) ([]*BranchPolicy, error) {
+	var all []*BranchPolicy
+	for page := 1; page != 0; {
+		req, err := in.c.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/environments/%s/deployment-branch-policies?per_page=30&page=%d", org, repoName, url.PathEscape(env), page), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var list branchPolicies
+		resp, err := in.c.Do(ctx, req, &list)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, list.BranchPolicies...)
+		page = resp.NextPage
+	}
+	return all, nil
+}
+
+func (in *client) CreateBranchPolicy(ctx context.Context, org, repoName, env, name string) error {
+	return in.do(ctx, http.MethodPost, fmt.Sprintf("repos/%s/%s/environments/%s/deployment-branch-policies", org, repoName, url.PathEscape(env)), &BranchPolicy{Name: name}, nil)
+}
+
+func (in *client) DeleteBranchPolicy(ctx context.Context, org, repoName, env string, id int64) error {
+	return in.doDelete(ctx, fmt.Sprintf("repos/%s/%s/environments/%s/deployment-branch-policies/%d", org, repoName, url.PathEscape(env), id))
+}
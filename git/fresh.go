@@ -0,0 +1,44 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"net/http"
+)
+
+type freshKey struct{}
+
+// WithFreshReads returns a context whose requests ask GitHub and every proxy
+// in between not to answer from a cache
+func WithFreshReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshKey{}, true)
+}
+
+// freshTransport sets the no-cache directive on the requests of a context
+// created with WithFreshReads
+type freshTransport struct {
+	next http.RoundTripper
+}
+
+func (t *freshTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if fresh, _ := req.Context().Value(freshKey{}).(bool); fresh {
+		req = req.Clone(req.Context())
+		req.Header.Set("Cache-Control", "no-cache")
+	}
+	return t.next.RoundTrip(req)
+}
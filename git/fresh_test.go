@@ -0,0 +1,60 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFreshTransport(t *testing.T) {
+	var cacheControl string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cacheControl = r.Header.Get("Cache-Control")
+	}))
+	defer server.Close()
+
+	cl := &http.Client{Transport: &freshTransport{next: http.DefaultTransport}}
+	tests := []struct {
+		ctx      context.Context
+		expected string
+	}{
+		{ctx: context.Background(), expected: ""},
+		{ctx: WithFreshReads(context.Background()), expected: "no-cache"},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp, err := cl.Do(req.WithContext(tt.ctx))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if cacheControl != tt.expected {
+			t.Errorf("expected Cache-Control %q, got %q", tt.expected, cacheControl)
+		}
+		if req.Header.Get("Cache-Control") != "" {
+			t.Error("expected the original request to be left alone")
+		}
+	}
+}
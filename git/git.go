@@ -22,12 +22,14 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/google/go-github/v28/github"
 	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/mirror"
 	"golang.org/x/oauth2"
 )
 
@@ -36,12 +38,36 @@ type Client interface {
 	// GetRepo will find the remote repo or error
 	GetRepo(context.Context, string, string) (*github.Repository, *github.Response, error)
 
+	// GetRepoByID will find the remote repo by its ID or error
+	GetRepoByID(context.Context, int64) (*github.Repository, *github.Response, error)
+
 	// CreateRepo will create a repo based on the params
 	CreateRepo(context.Context, string, *v1alpha1.Repository) error
 
 	// DeleteRepo will delete the repo
 	DeleteRepo(context.Context, string, string) error
 
+	// EditRepo will update the repo with the non empty fields
+	EditRepo(context.Context, string, string, *github.Repository) (*github.Repository, error)
+
+	// TransferRepo will transfer the repo to a new owner
+	TransferRepo(context.Context, string, string, string) error
+
+	// RepoInitialized will report whether the repo has at least one commit
+	RepoInitialized(context.Context, string, string) (bool, error)
+
+	// ImportRepo will copy the branches and tags of the source git URL into the repo at the clone URL
+	ImportRepo(context.Context, string, string, *mirror.Credentials) error
+
+	// CompareCommits will count the commits the head is ahead and behind of the base
+	CompareCommits(context.Context, string, string, string, string) (int, int, error)
+
+	// RenameBranch will rename a branch of the repo
+	RenameBranch(context.Context, string, string, string, string) error
+
+	// BranchExists will report whether the repo has the branch
+	BranchExists(context.Context, string, string, string) (bool, error)
+
 	// GetKey will find the remote key or error
 	GetKey(context.Context, string, string, int64) (*github.Key, *github.Response, error)
 
@@ -50,6 +76,126 @@ type Client interface {
 
 	// DeleteKey will delete the key from the repo
 	DeleteKey(context.Context, string, string, int64) error
+
+	// CreateOrUpdateRepoSecret will encrypt and upload an Actions secret to the repo
+	CreateOrUpdateRepoSecret(context.Context, string, string, string, []byte) error
+
+	// DeleteRepoSecret will delete the Actions secret from the repo
+	DeleteRepoSecret(context.Context, string, string, string) error
+
+	// CreateOrUpdateOrgSecret will encrypt and upload an Actions secret to the org
+	CreateOrUpdateOrgSecret(context.Context, string, string, string, []int64, []byte) error
+
+	// DeleteOrgSecret will delete the Actions secret from the org
+	DeleteOrgSecret(context.Context, string, string) error
+
+	// ListVariables will list the Actions variables of the repo
+	ListVariables(context.Context, string, string) ([]*Variable, error)
+
+	// CreateVariable will create an Actions variable in the repo
+	CreateVariable(context.Context, string, string, *Variable) error
+
+	// UpdateVariable will update the value of an Actions variable in the repo
+	UpdateVariable(context.Context, string, string, *Variable) error
+
+	// DeleteVariable will delete the Actions variable from the repo
+	DeleteVariable(context.Context, string, string, string) error
+
+	// ListEnvironments will list the deployment environments of the repo
+	ListEnvironments(context.Context, string, string) ([]*Environment, error)
+
+	// CreateOrUpdateEnvironment will create or update the environment and its protection rules
+	CreateOrUpdateEnvironment(context.Context, string, string, *v1alpha1.RepositoryEnvironment) error
+
+	// DeleteEnvironment will delete the environment from the repo
+	DeleteEnvironment(context.Context, string, string, string) error
+
+	// ListBranchPolicies will list the deployment branch policies of the environment
+	ListBranchPolicies(context.Context, string, string, string) ([]*BranchPolicy, error)
+
+	// CreateBranchPolicy will allow the branch name pattern to deploy to the environment
+	CreateBranchPolicy(context.Context, string, string, string, string) error
+
+	// DeleteBranchPolicy will delete the deployment branch policy from the environment
+	DeleteBranchPolicy(context.Context, string, string, string, int64) error
+
+	// GetSecurity will find the enabled security and analysis features of the repo
+	GetSecurity(context.Context, string, string) (*v1alpha1.RepositorySecurityStatus, error)
+
+	// UpdateSecurity will enable or disable the security and analysis features that are set
+	UpdateSecurity(context.Context, string, string, *v1alpha1.RepositorySecurity) error
+
+	// ListLabels will list the issue labels of the repo
+	ListLabels(context.Context, string, string) ([]*github.Label, error)
+
+	// CreateLabel will create an issue label in the repo
+	CreateLabel(context.Context, string, string, *github.Label) error
+
+	// EditLabel will update the color and description of an issue label
+	EditLabel(context.Context, string, string, string, *github.Label) error
+
+	// DeleteLabel will delete the issue label from the repo
+	DeleteLabel(context.Context, string, string, string) error
+
+	// GetFile will find the file at the path and ref of the repo or error
+	GetFile(context.Context, string, string, string, string) (*github.RepositoryContent, *github.Response, error)
+
+	// CommitFile will create or update the file at the path, it is updated when a SHA is set
+	CommitFile(context.Context, string, string, string, *github.RepositoryContentFileOptions) error
+
+	// DeleteFile will delete the file at the path
+	DeleteFile(context.Context, string, string, string, *github.RepositoryContentFileOptions) error
+
+	// GetBranch will find the branch of the repo or error
+	GetBranch(context.Context, string, string, string) (*github.Branch, *github.Response, error)
+
+	// CreateBranch will create a branch pointing at the commit SHA
+	CreateBranch(context.Context, string, string, string, string) error
+
+	// FindPullRequest will find the open pull request from the head into the base branch, nil when there is none
+	FindPullRequest(context.Context, string, string, string, string) (*github.PullRequest, error)
+
+	// CreatePullRequest will open a pull request in the repo
+	CreatePullRequest(context.Context, string, string, *github.NewPullRequest) (*github.PullRequest, error)
+
+	// GetPages will find the Pages site of the repo, nil when it is not enabled
+	GetPages(context.Context, string, string) (*Pages, error)
+
+	// CreatePages will enable the Pages site of the repo
+	CreatePages(context.Context, string, string, *Pages) error
+
+	// UpdatePages will update the source, domain and HTTPS settings of the Pages site
+	UpdatePages(context.Context, string, string, *Pages) error
+
+	// DeletePages will disable the Pages site of the repo
+	DeletePages(context.Context, string, string) error
+
+	// ListAutolinks will list the autolink references of the repo
+	ListAutolinks(context.Context, string, string) ([]*Autolink, error)
+
+	// CreateAutolink will create an autolink reference in the repo
+	CreateAutolink(context.Context, string, string, *Autolink) error
+
+	// DeleteAutolink will delete the autolink reference from the repo
+	DeleteAutolink(context.Context, string, string, int64) error
+
+	// GetCustomProperty will find the custom property schema of the org, nil when it does not exist
+	GetCustomProperty(context.Context, string, string) (*CustomProperty, error)
+
+	// CreateOrUpdateCustomProperty will create or update the custom property schema of the org
+	CreateOrUpdateCustomProperty(context.Context, string, string, *CustomProperty) error
+
+	// DeleteCustomProperty will delete the custom property schema from the org
+	DeleteCustomProperty(context.Context, string, string) error
+
+	// GetCustomPropertyValues will list the custom property values of the repo
+	GetCustomPropertyValues(context.Context, string, string) ([]v1alpha1.RepositoryCustomProperty, error)
+
+	// SetCustomPropertyValues will set the custom property values of the repo, properties without values are unset
+	SetCustomPropertyValues(context.Context, string, string, []v1alpha1.RepositoryCustomProperty) error
+
+	// RateLimit will find the core rate limit of the token or error when the token is not valid
+	RateLimit(context.Context) (*github.Rate, error)
 }
 
 type client struct {
@@ -63,6 +209,7 @@ func New(ctx context.Context, token string) (cl Client, err error) {
 	cli := &client{}
 	cli.ts = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
 	cli.tc = oauth2.NewClient(ctx, cli.ts)
+	cli.tc.Transport = &instrumentedTransport{next: &freshTransport{next: cli.tc.Transport}}
 
 	if url := os.Getenv("GITHUB_ENTERPRISE_URL"); url != "" {
 		if cli.c, err = github.NewEnterpriseClient(url, url, cli.tc); err != nil {
@@ -83,10 +230,18 @@ func (in *client) GetRepo(ctx context.Context, org, name string) (repo *github.R
 	return repo, resp, nil
 }
 
+func (in *client) GetRepoByID(ctx context.Context, id int64) (*github.Repository, *github.Response, error) {
+	return in.c.Repositories.GetByID(ctx, id)
+}
+
 func (in *client) CreateRepo(ctx context.Context, org string, repo *v1alpha1.Repository) error {
+	if repo.Spec.FromTemplate != nil {
+		return in.createRepoFromTemplate(ctx, org, repo)
+	}
+
 	authUser, _, err := in.c.Users.Get(ctx, "")
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	if authUser.GetLogin() == org {
@@ -94,14 +249,148 @@ func (in *client) CreateRepo(ctx context.Context, org string, repo *v1alpha1.Rep
 		org = "" // pass an empty string, only for repository creation
 	}
 
+	if repo.Spec.ForkFrom != nil {
+		return in.createFork(ctx, org, repo)
+	}
+
+	// imports are pushed into an empty repository
+	opts := repo.Spec.Init
+	if repo.Spec.Import != nil {
+		opts = v1alpha1.RepositoryInit{}
+	}
+
 	r := newRepository(repo)
-	_, _, err = in.c.Repositories.Create(ctx, org, r)
+	r.AutoInit = &opts.AutoInit
+	r.GitignoreTemplate = &opts.GitignoreTemplate
+	r.LicenseTemplate = &opts.LicenseTemplate
+	created, _, err := in.c.Repositories.Create(ctx, org, r)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+
+	// custom properties drive rulesets, set them before anything is pushed
+	if org != "" && len(repo.Spec.CustomProperties) > 0 {
+		return in.SetCustomPropertyValues(ctx, org, created.GetName(), repo.Spec.CustomProperties)
+	}
+	return nil
+}
+
+func (in *client) RenameBranch(ctx context.Context, org, name, branch, newName string) error {
+	body := struct {
+		NewName string `json:"new_name"`
+	}{newName}
+	return in.do(ctx, http.MethodPost, fmt.Sprintf("repos/%s/%s/branches/%s/rename", org, name, url.PathEscape(branch)), body, nil)
+}
+
+func (in *client) BranchExists(ctx context.Context, org, name, branch string) (bool, error) {
+	req, err := in.c.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/branches/%s", org, name, url.PathEscape(branch)), nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := in.c.Do(ctx, req, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// templateRepoRequest is the request body for generating a repository from a template
+type templateRepoRequest struct {
+	Owner              string `json:"owner"`
+	Name               string `json:"name"`
+	Description        string `json:"description,omitempty"`
+	Private            bool   `json:"private"`
+	IncludeAllBranches bool   `json:"include_all_branches"`
+}
+
+// createRepoFromTemplate generates the repo from its template, the contents are
+// copied asynchronously so the repo can be empty for a little while
+func (in *client) createRepoFromTemplate(ctx context.Context, org string, repo *v1alpha1.Repository) error {
+	tmpl := repo.Spec.FromTemplate
+	body := &templateRepoRequest{
+		Owner:              org,
+		Name:               repo.GitHubName(),
+		Description:        repo.Spec.Description,
+		Private:            repo.Spec.Settings.Private,
+		IncludeAllBranches: tmpl.IncludeAllBranches,
+	}
+	if err := in.do(ctx, http.MethodPost, fmt.Sprintf("repos/%s/%s/generate", tmpl.Owner, tmpl.Repository), body, nil); err != nil {
+		return err
+	}
+
+	// the generate endpoint does not take the remaining settings
+	if _, _, err := in.c.Repositories.Edit(ctx, org, repo.GitHubName(), newRepository(repo)); err != nil {
+		return err
+	}
+
+	if len(repo.Spec.CustomProperties) > 0 {
+		return in.SetCustomPropertyValues(ctx, org, repo.GitHubName(), repo.Spec.CustomProperties)
 	}
 	return nil
 }
 
+// forkRequest is the request body for forking a repository
+type forkRequest struct {
+	Organization      string `json:"organization,omitempty"`
+	Name              string `json:"name"`
+	DefaultBranchOnly bool   `json:"default_branch_only"`
+}
+
+// createFork forks the upstream repo into org, the personal account when org
+// is empty. Forks are created asynchronously so the repo can be missing or
+// empty for a little while.
+func (in *client) createFork(ctx context.Context, org string, repo *v1alpha1.Repository) error {
+	fork := repo.Spec.ForkFrom
+	body := &forkRequest{
+		Organization:      org,
+		Name:              repo.GitHubName(),
+		DefaultBranchOnly: fork.DefaultBranchOnly,
+	}
+	err := in.do(ctx, http.MethodPost, fmt.Sprintf("repos/%s/%s/forks", fork.Owner, fork.Repository), body, nil)
+	if _, ok := err.(*github.AcceptedError); ok {
+		return nil
+	}
+	return err
+}
+
+func (in *client) ImportRepo(ctx context.Context, cloneURL, source string, creds *mirror.Credentials) error {
+	// the controller decides whether insecure sources are allowed, sources git
+	// can't fetch with the credentials are never accepted
+	if err := mirror.ValidateSource(source, true); err != nil {
+		return err
+	}
+	token, err := in.ts.Token()
+	if err != nil {
+		return err
+	}
+	return mirror.Mirror(ctx, source, creds, cloneURL, &mirror.Credentials{Username: "x-access-token", Password: token.AccessToken})
+}
+
+func (in *client) CompareCommits(ctx context.Context, org, name, base, head string) (int, int, error) {
+	comparison, _, err := in.c.Repositories.CompareCommits(ctx, org, name, base, head)
+	if err != nil {
+		return 0, 0, err
+	}
+	return comparison.GetAheadBy(), comparison.GetBehindBy(), nil
+}
+
+func (in *client) RepoInitialized(ctx context.Context, org, name string) (bool, error) {
+	_, resp, err := in.c.Repositories.ListCommits(ctx, org, name, &github.CommitsListOptions{
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		// an empty repository has no commits to list
+		if resp != nil && resp.StatusCode == http.StatusConflict {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 func (in *client) DeleteRepo(ctx context.Context, org, name string) error {
 	resp, err := in.c.Repositories.Delete(ctx, org, name)
 	if err != nil {
@@ -114,9 +403,24 @@ func (in *client) DeleteRepo(ctx context.Context, org, name string) error {
 	return nil
 }
 
+func (in *client) EditRepo(ctx context.Context, org, name string, repo *github.Repository) (*github.Repository, error) {
+	edited, _, err := in.c.Repositories.Edit(ctx, org, name, repo)
+	return edited, err
+}
+
+func (in *client) TransferRepo(ctx context.Context, org, name, newOwner string) error {
+	_, _, err := in.c.Repositories.Transfer(ctx, org, name, github.TransferRequest{NewOwner: newOwner})
+	if _, ok := err.(*github.AcceptedError); ok {
+		// transfers are processed asynchronously
+		return nil
+	}
+	return err
+}
+
 func newRepository(repo *v1alpha1.Repository) *github.Repository {
+	name := repo.GitHubName()
 	return &github.Repository{
-		Name:        &repo.Name,
+		Name:        &name,
 		Description: &repo.Spec.Description,
 		Homepage:    &repo.Spec.Homepage,
 		Private:     &repo.Spec.Settings.Private,
@@ -161,6 +465,14 @@ func (in *client) DeleteKey(ctx context.Context, org, name string, keyID int64)
 	return nil
 }
 
+func (in *client) RateLimit(ctx context.Context) (*github.Rate, error) {
+	limits, _, err := in.c.RateLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return limits.GetCore(), nil
+}
+
 func newKey(key *v1alpha1.Key, secret *corev1.Secret) (*github.Key, error) {
 	publicKey := string(secret.Data["identity.pub"])
 	if publicKey == "" {
@@ -173,3 +485,32 @@ func newKey(key *v1alpha1.Key, secret *corev1.Secret) (*github.Key, error) {
 		ReadOnly: &key.Spec.ReadOnly,
 	}, nil
 }
+
+// do sends a request for an endpoint go-github does not cover and decodes the response into v
+func (in *client) do(ctx context.Context, method, u string, body, v interface{}) error {
+	req, err := in.c.NewRequest(method, u, body)
+	if err != nil {
+		return err
+	}
+
+	_, err = in.c.Do(ctx, req, v)
+	return err
+}
+
+// doDelete sends a DELETE request, a missing object is only logged
+func (in *client) doDelete(ctx context.Context, u string) error {
+	req, err := in.c.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := in.c.Do(ctx, req, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			log.Printf("WARNING\t%v", err)
+		} else {
+			return err
+		}
+	}
+	return nil
+}
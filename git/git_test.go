@@ -0,0 +1,40 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRenameBranchEscapesName(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	if err := newServerClient(t, server).RenameBranch(context.Background(), "org", "repo", "feature/x", "main"); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "/repos/org/repo/branches/feature%2Fx/rename"; path != expected {
+		t.Fatalf("expected %s, got %s", expected, path)
+	}
+}
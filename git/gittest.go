@@ -26,6 +26,7 @@ import (
 
 	"github.com/google/go-github/v28/github"
 	"go.hein.dev/github-controller/api/v1alpha1"
+	"go.hein.dev/github-controller/mirror"
 )
 
 // TestClient will generate a test stub
@@ -33,6 +34,15 @@ func TestClient() Client {
 	return &testclient{
 		RepositoryCreated: false,
 		RepositoryDeleted: false,
+		Secrets:           map[string][]byte{},
+		Variables:         map[string]*Variable{},
+		Environments:      map[string]*Environment{},
+		Security:          &v1alpha1.RepositorySecurityStatus{},
+		Labels:            map[string]*github.Label{},
+		Files:             map[string]*github.RepositoryContent{},
+		Autolinks:         map[int64]*Autolink{},
+		CustomProperties:  map[string]*CustomProperty{},
+		PropertyValues:    map[string]v1alpha1.RepositoryCustomProperty{},
 	}
 }
 
@@ -42,6 +52,17 @@ type testclient struct {
 	RepositoryDeleted bool
 	KeyCreated        bool
 	KeyDeleted        bool
+	Secrets           map[string][]byte
+	Variables         map[string]*Variable
+	Environments      map[string]*Environment
+	Security          *v1alpha1.RepositorySecurityStatus
+	Labels            map[string]*github.Label
+	Files             map[string]*github.RepositoryContent
+	Pages             *Pages
+	Autolinks         map[int64]*Autolink
+	CustomProperties  map[string]*CustomProperty
+	PropertyValues    map[string]v1alpha1.RepositoryCustomProperty
+	Imported          []string
 }
 
 func (in *testclient) GetRepo(ctx context.Context, org, name string) (*github.Repository, *github.Response, error) {
@@ -49,7 +70,7 @@ func (in *testclient) GetRepo(ctx context.Context, org, name string) (*github.Re
 		resp := &github.Response{
 			Response: &http.Response{StatusCode: http.StatusOK},
 		}
-		return &github.Repository{}, resp, nil
+		return &github.Repository{Name: &name, Owner: &github.User{Login: &org}}, resp, nil
 	}
 	resp := &github.Response{
 		Response: &http.Response{StatusCode: http.StatusNotFound},
@@ -57,6 +78,13 @@ func (in *testclient) GetRepo(ctx context.Context, org, name string) (*github.Re
 	return &github.Repository{}, resp, fmt.Errorf("not found")
 }
 
+func (in *testclient) GetRepoByID(ctx context.Context, id int64) (*github.Repository, *github.Response, error) {
+	resp := &github.Response{
+		Response: &http.Response{StatusCode: http.StatusNotFound},
+	}
+	return &github.Repository{}, resp, fmt.Errorf("not found")
+}
+
 func (in *testclient) CreateRepo(ctx context.Context, org string, repo *v1alpha1.Repository) error {
 	in.RepositoryCreated = true
 	in.RepositoryDeleted = false
@@ -69,6 +97,26 @@ func (in *testclient) DeleteRepo(ctx context.Context, org, name string) error {
 	return nil
 }
 
+func (in *testclient) EditRepo(ctx context.Context, org, name string, repo *github.Repository) (*github.Repository, error) {
+	return repo, nil
+}
+
+func (in *testclient) TransferRepo(ctx context.Context, org, name, newOwner string) error {
+	return nil
+}
+
+func (in *testclient) RepoInitialized(ctx context.Context, org, name string) (bool, error) {
+	return in.RepositoryCreated, nil
+}
+
+func (in *testclient) RenameBranch(ctx context.Context, org, name, branch, newName string) error {
+	return nil
+}
+
+func (in *testclient) BranchExists(ctx context.Context, org, name, branch string) (bool, error) {
+	return false, nil
+}
+
 func (in *testclient) GetKey(ctx context.Context, org, repoName string, keyID int64) (*github.Key, *github.Response, error) {
 	if in.KeyCreated {
 		resp := &github.Response{
@@ -106,3 +154,262 @@ func (in *testclient) DeleteKey(ctx context.Context, org, name string, keyID int
 	in.KeyDeleted = true
 	return nil
 }
+
+func (in *testclient) CreateOrUpdateRepoSecret(ctx context.Context, org, repoName, name string, value []byte) error {
+	in.Secrets[fmt.Sprintf("%s/%s/%s", org, repoName, name)] = value
+	return nil
+}
+
+func (in *testclient) DeleteRepoSecret(ctx context.Context, org, repoName, name string) error {
+	delete(in.Secrets, fmt.Sprintf("%s/%s/%s", org, repoName, name))
+	return nil
+}
+
+func (in *testclient) CreateOrUpdateOrgSecret(ctx context.Context, org, name, visibility string, selectedRepositoryIDs []int64, value []byte) error {
+	in.Secrets[fmt.Sprintf("%s/%s", org, name)] = value
+	return nil
+}
+
+func (in *testclient) DeleteOrgSecret(ctx context.Context, org, name string) error {
+	delete(in.Secrets, fmt.Sprintf("%s/%s", org, name))
+	return nil
+}
+
+func (in *testclient) ListVariables(ctx context.Context, org, repoName string) ([]*Variable, error) {
+	var variables []*Variable
+	for _, variable := range in.Variables {
+		variables = append(variables, variable)
+	}
+	return variables, nil
+}
+
+func (in *testclient) CreateVariable(ctx context.Context, org, repoName string, variable *Variable) error {
+	in.Variables[variable.Name] = variable
+	return nil
+}
+
+func (in *testclient) UpdateVariable(ctx context.Context, org, repoName string, variable *Variable) error {
+	in.Variables[variable.Name] = variable
+	return nil
+}
+
+func (in *testclient) DeleteVariable(ctx context.Context, org, repoName, name string) error {
+	delete(in.Variables, name)
+	return nil
+}
+
+func (in *testclient) ListEnvironments(ctx context.Context, org, repoName string) ([]*Environment, error) {
+	var environments []*Environment
+	for _, env := range in.Environments {
+		environments = append(environments, env)
+	}
+	return environments, nil
+}
+
+func (in *testclient) CreateOrUpdateEnvironment(ctx context.Context, org, repoName string, env *v1alpha1.RepositoryEnvironment) error {
+	in.Environments[env.Name] = &Environment{
+		Name: env.Name,
+		ProtectionRules: []*ProtectionRule{
+			{Type: "wait_timer", WaitTimer: env.WaitTimer},
+		},
+	}
+	return nil
+}
+
+func (in *testclient) DeleteEnvironment(ctx context.Context, org, repoName, name string) error {
+	delete(in.Environments, name)
+	return nil
+}
+
+func (in *testclient) ListBranchPolicies(ctx context.Context, org, repoName, env string) ([]*BranchPolicy, error) {
+	return nil, nil
+}
+
+func (in *testclient) CreateBranchPolicy(ctx context.Context, org, repoName, env, name string) error {
+	return nil
+}
+
+func (in *testclient) DeleteBranchPolicy(ctx context.Context, org, repoName, env string, id int64) error {
+	return nil
+}
+
+func (in *testclient) GetSecurity(ctx context.Context, org, repoName string) (*v1alpha1.RepositorySecurityStatus, error) {
+	return in.Security.DeepCopy(), nil
+}
+
+func (in *testclient) UpdateSecurity(ctx context.Context, org, repoName string, security *v1alpha1.RepositorySecurity) error {
+	set := func(current *bool, desired *bool) {
+		if desired != nil {
+			*current = *desired
+		}
+	}
+	set(&in.Security.DependabotAlerts, security.DependabotAlerts)
+	set(&in.Security.DependabotSecurityUpdates, security.DependabotSecurityUpdates)
+	set(&in.Security.SecretScanning, security.SecretScanning)
+	set(&in.Security.SecretScanningPushProtection, security.SecretScanningPushProtection)
+	set(&in.Security.PrivateVulnerabilityReporting, security.PrivateVulnerabilityReporting)
+	return nil
+}
+
+func (in *testclient) ListLabels(ctx context.Context, org, repoName string) ([]*github.Label, error) {
+	labels := []*github.Label{}
+	for _, label := range in.Labels {
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+func (in *testclient) CreateLabel(ctx context.Context, org, repoName string, label *github.Label) error {
+	in.Labels[label.GetName()] = label
+	return nil
+}
+
+func (in *testclient) EditLabel(ctx context.Context, org, repoName, name string, label *github.Label) error {
+	delete(in.Labels, name)
+	in.Labels[label.GetName()] = label
+	return nil
+}
+
+func (in *testclient) DeleteLabel(ctx context.Context, org, repoName, name string) error {
+	delete(in.Labels, name)
+	return nil
+}
+
+func (in *testclient) GetFile(ctx context.Context, org, repoName, path, ref string) (*github.RepositoryContent, *github.Response, error) {
+	if file, ok := in.Files[ref+":"+path]; ok {
+		resp := &github.Response{
+			Response: &http.Response{StatusCode: http.StatusOK},
+		}
+		return file, resp, nil
+	}
+	resp := &github.Response{
+		Response: &http.Response{StatusCode: http.StatusNotFound},
+	}
+	return nil, resp, fmt.Errorf("not found")
+}
+
+func (in *testclient) CommitFile(ctx context.Context, org, repoName, path string, opts *github.RepositoryContentFileOptions) error {
+	in.Files[opts.GetBranch()+":"+path] = &github.RepositoryContent{
+		Path: &path,
+		SHA:  github.String(BlobSHA(opts.Content)),
+	}
+	return nil
+}
+
+func (in *testclient) DeleteFile(ctx context.Context, org, repoName, path string, opts *github.RepositoryContentFileOptions) error {
+	delete(in.Files, opts.GetBranch()+":"+path)
+	return nil
+}
+
+func (in *testclient) GetBranch(ctx context.Context, org, repoName, branch string) (*github.Branch, *github.Response, error) {
+	resp := &github.Response{
+		Response: &http.Response{StatusCode: http.StatusOK},
+	}
+	return &github.Branch{Name: &branch, Protected: github.Bool(false)}, resp, nil
+}
+
+func (in *testclient) CreateBranch(ctx context.Context, org, repoName, branch, sha string) error {
+	return nil
+}
+
+func (in *testclient) FindPullRequest(ctx context.Context, org, repoName, head, base string) (*github.PullRequest, error) {
+	return nil, nil
+}
+
+func (in *testclient) CreatePullRequest(ctx context.Context, org, repoName string, pull *github.NewPullRequest) (*github.PullRequest, error) {
+	return &github.PullRequest{Title: pull.Title}, nil
+}
+
+func (in *testclient) GetPages(ctx context.Context, org, repoName string) (*Pages, error) {
+	return in.Pages, nil
+}
+
+func (in *testclient) CreatePages(ctx context.Context, org, repoName string, pages *Pages) error {
+	in.Pages = &Pages{
+		HTMLURL:   fmt.Sprintf("https://%s.github.io/%s/", org, repoName),
+		Status:    "building",
+		BuildType: pages.BuildType,
+		Source:    pages.Source,
+	}
+	return nil
+}
+
+func (in *testclient) UpdatePages(ctx context.Context, org, repoName string, pages *Pages) error {
+	in.Pages.CNAME = pages.CNAME
+	in.Pages.HTTPSEnforced = pages.HTTPSEnforced
+	in.Pages.BuildType = pages.BuildType
+	in.Pages.Source = pages.Source
+	return nil
+}
+
+func (in *testclient) DeletePages(ctx context.Context, org, repoName string) error {
+	in.Pages = nil
+	return nil
+}
+
+func (in *testclient) ListAutolinks(ctx context.Context, org, repoName string) ([]*Autolink, error) {
+	autolinks := []*Autolink{}
+	for _, autolink := range in.Autolinks {
+		autolinks = append(autolinks, autolink)
+	}
+	return autolinks, nil
+}
+
+func (in *testclient) CreateAutolink(ctx context.Context, org, repoName string, autolink *Autolink) error {
+	in.IdCounter++
+	created := *autolink
+	created.ID = in.IdCounter
+	in.Autolinks[created.ID] = &created
+	return nil
+}
+
+func (in *testclient) DeleteAutolink(ctx context.Context, org, repoName string, id int64) error {
+	delete(in.Autolinks, id)
+	return nil
+}
+
+func (in *testclient) GetCustomProperty(ctx context.Context, org, name string) (*CustomProperty, error) {
+	return in.CustomProperties[name], nil
+}
+
+func (in *testclient) CreateOrUpdateCustomProperty(ctx context.Context, org, name string, property *CustomProperty) error {
+	in.CustomProperties[name] = property
+	return nil
+}
+
+func (in *testclient) DeleteCustomProperty(ctx context.Context, org, name string) error {
+	delete(in.CustomProperties, name)
+	return nil
+}
+
+func (in *testclient) GetCustomPropertyValues(ctx context.Context, org, repoName string) ([]v1alpha1.RepositoryCustomProperty, error) {
+	properties := []v1alpha1.RepositoryCustomProperty{}
+	for _, property := range in.PropertyValues {
+		properties = append(properties, property)
+	}
+	return properties, nil
+}
+
+func (in *testclient) SetCustomPropertyValues(ctx context.Context, org, repoName string, properties []v1alpha1.RepositoryCustomProperty) error {
+	for _, property := range properties {
+		if property.Value == "" && len(property.Values) == 0 {
+			delete(in.PropertyValues, property.Name)
+			continue
+		}
+		in.PropertyValues[property.Name] = property
+	}
+	return nil
+}
+
+func (in *testclient) CompareCommits(ctx context.Context, org, name, base, head string) (int, int, error) {
+	return 0, 0, nil
+}
+
+func (in *testclient) ImportRepo(ctx context.Context, cloneURL, source string, creds *mirror.Credentials) error {
+	in.Imported = append(in.Imported, source)
+	return nil
+}
+
+func (in *testclient) RateLimit(ctx context.Context) (*github.Rate, error) {
+	return &github.Rate{Limit: 5000, Remaining: 5000}, nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ReadyCheck reports whether GitHub accepts the token and has requests left,
+// the result is cached for the TTL so probes don't use up the rate limit
+type ReadyCheck struct {
+	Client Client
+	TTL    time.Duration
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+// Check implements the healthz.Checker of the manager
+func (in *ReadyCheck) Check(req *http.Request) error {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if !in.checked.IsZero() && time.Since(in.checked) < in.TTL {
+		return in.err
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
+	defer cancel()
+
+	in.err = nil
+	rate, err := in.Client.RateLimit(ctx)
+	switch {
+	case err != nil:
+		in.err = fmt.Errorf("unable to reach github: %v", err)
+	case rate.Remaining == 0 && rate.Reset.After(time.Now()):
+		in.err = fmt.Errorf("github rate limit of %d requests exhausted until %s", rate.Limit, rate.Reset.Format(time.RFC3339))
+	}
+	in.checked = time.Now()
+
+	return in.err
+}
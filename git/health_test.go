@@ -0,0 +1,69 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v28/github"
+)
+
+type rateClient struct {
+	Client
+	rate  *github.Rate
+	err   error
+	calls int
+}
+
+func (in *rateClient) RateLimit(ctx context.Context) (*github.Rate, error) {
+	in.calls++
+	return in.rate, in.err
+}
+
+func TestReadyCheck(t *testing.T) {
+	req := httptest.NewRequest("GET", "/readyz", nil)
+
+	cl := &rateClient{rate: &github.Rate{Limit: 5000, Remaining: 10}}
+	check := &ReadyCheck{Client: cl, TTL: time.Minute}
+	if err := check.Check(req); err != nil {
+		t.Fatalf("expected ready, got %v", err)
+	}
+
+	cl.err = fmt.Errorf("401 Bad credentials")
+	if err := check.Check(req); err != nil {
+		t.Fatalf("expected the cached result, got %v", err)
+	}
+	if cl.calls != 1 {
+		t.Fatalf("expected a single call to github, got %d", cl.calls)
+	}
+
+	check.checked = time.Now().Add(-2 * time.Minute)
+	if err := check.Check(req); err == nil {
+		t.Fatal("expected an invalid token to be unready")
+	}
+
+	cl.err = nil
+	cl.rate = &github.Rate{Limit: 5000, Remaining: 0, Reset: github.Timestamp{Time: time.Now().Add(time.Hour)}}
+	check.checked = time.Time{}
+	if err := check.Check(req); err == nil {
+		t.Fatal("expected an exhausted rate limit to be unready")
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/google/go-github/v28/github"
+)
+
+func (in *client) ListLabels(ctx context.Context, org, repoName string) ([]*github.Label, error) {
+	var all []*github.Label
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		labels, resp, err := in.c.Issues.ListLabels(ctx, org, repoName, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, labels...)
+		if resp.NextPage == 0 {
+			return all, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (in *client) CreateLabel(ctx context.Context, org, repoName string, label *github.Label) error {
+	_, _, err := in.c.Issues.CreateLabel(ctx, org, repoName, label)
+	return err
+}
+
+func (in *client) EditLabel(ctx context.Context, org, repoName, name string, label *github.Label) error {
+	_, _, err := in.c.Issues.EditLabel(ctx, org, repoName, name, label)
+	return err
+}
+
+func (in *client) DeleteLabel(ctx context.Context, org, repoName, name string) error {
+	resp, err := in.c.Issues.DeleteLabel(ctx, org, repoName, name)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			log.Printf("WARNING\t%v", err)
+		} else {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "github_controller_github_requests_total",
+		Help: "Number of requests sent to the GitHub API by method, endpoint and status code.",
+	}, []string{"method", "endpoint", "code"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "github_controller_github_request_duration_seconds",
+		Help:    "Latency of the requests sent to the GitHub API by method and endpoint.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "endpoint"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(requestsTotal, requestDuration)
+}
+
+// pathParameters are the leading path segments that are followed by a fixed
+// number of parameters, e.g. /repos/{owner}/{repo}
+var pathParameters = map[string][]string{
+	"repos":        {"{owner}", "{repo}"},
+	"orgs":         {"{org}"},
+	"users":        {"{user}"},
+	"repositories": {"{id}"},
+}
+
+// pathRemainders are the path segments after which the rest of the path is a
+// single parameter that can contain slashes, like a file path or a branch
+var pathRemainders = map[string]string{
+	"contents": "{path}",
+	"heads":    "{branch}",
+	"compare":  "{basehead}",
+}
+
+// pathResources are the path segments that name a resource, every other
+// segment is a parameter
+var pathResources = map[string]bool{
+	"actions":                         true,
+	"autolinks":                       true,
+	"automated-security-fixes":        true,
+	"branches":                        true,
+	"commits":                         true,
+	"deployment-branch-policies":      true,
+	"environments":                    true,
+	"forks":                           true,
+	"generate":                        true,
+	"git":                             true,
+	"keys":                            true,
+	"labels":                          true,
+	"pages":                           true,
+	"private-vulnerability-reporting": true,
+	"properties":                      true,
+	"public-key":                      true,
+	"pulls":                           true,
+	"rate_limit":                      true,
+	"ref":                             true,
+	"refs":                            true,
+	"rename":                          true,
+	"repos":                           true,
+	"schema":                          true,
+	"secrets":                         true,
+	"transfer":                        true,
+	"user":                            true,
+	"values":                          true,
+	"variables":                       true,
+	"vulnerability-alerts":            true,
+}
+
+// endpoint turns the path of a GitHub API request into its route by replacing
+// the parameters, so the metrics don't get a label value per object
+func endpoint(path string) string {
+	path = strings.TrimPrefix(path, "/api/v3")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	route := make([]string, 0, len(segments))
+	for i := 0; i < len(segments); i++ {
+		segment := segments[i]
+		switch {
+		case i == 0 && pathParameters[segment] != nil:
+			route = append(route, segment)
+			for _, param := range pathParameters[segment] {
+				if i+1 < len(segments) {
+					route = append(route, param)
+					i++
+				}
+			}
+		case pathRemainders[segment] != "":
+			route = append(route, segment)
+			if i+1 < len(segments) {
+				route = append(route, pathRemainders[segment])
+			}
+			i = len(segments)
+		case pathResources[segment]:
+			route = append(route, segment)
+		default:
+			route = append(route, "{name}")
+		}
+	}
+	return "/" + strings.Join(route, "/")
+}
+
+// instrumentedTransport records the count, status code and latency of every
+// request sent to GitHub
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	route := endpoint(req.URL.Path)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	requestsTotal.WithLabelValues(req.Method, route, code).Inc()
+	requestDuration.WithLabelValues(req.Method, route).Observe(time.Since(start).Seconds())
+
+	return resp, err
+}
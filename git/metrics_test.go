@@ -0,0 +1,43 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import "testing"
+
+func TestEndpoint(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/user", expected: "/user"},
+		{path: "/orgs/acme/repos", expected: "/orgs/{org}/repos"},
+		{path: "/repos/acme/app", expected: "/repos/{owner}/{repo}"},
+		{path: "/repos/acme/repos/labels/bug", expected: "/repos/{owner}/{repo}/labels/{name}"},
+		{path: "/repos/acme/app/keys/42", expected: "/repos/{owner}/{repo}/keys/{name}"},
+		{path: "/repos/acme/app/contents/docs/README.md", expected: "/repos/{owner}/{repo}/contents/{path}"},
+		{path: "/repos/acme/app/git/refs/heads/feature/x", expected: "/repos/{owner}/{repo}/git/refs/heads/{branch}"},
+		{path: "/repos/acme/app/environments/prod/deployment-branch-policies/7", expected: "/repos/{owner}/{repo}/environments/{name}/deployment-branch-policies/{name}"},
+		{path: "/api/v3/orgs/acme/actions/secrets/TOKEN", expected: "/orgs/{org}/actions/secrets/{name}"},
+		{path: "/repositories/1296269", expected: "/repositories/{id}"},
+	}
+
+	for _, tt := range tests {
+		if route := endpoint(tt.path); route != tt.expected {
+			t.Errorf("expected endpoint of %s to be %s, got %s", tt.path, tt.expected, route)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Pages is a GitHub Pages site
+type Pages struct {
+	HTMLURL       string       `json:"html_url,omitempty"`
+	Status        string       `json:"status,omitempty"`
+	CNAME         *string      `json:"cname"`
+	HTTPSEnforced *bool        `json:"https_enforced,omitempty"`
+	BuildType     string       `json:"build_type,omitempty"`
+	Source        *PagesSource `json:"source,omitempty"`
+}
+
+// PagesSource is the branch and path a Pages site is built from
+type PagesSource struct {
+	Branch string `json:"branch"`
+	Path   string `json:"path,omitempty"`
+}
+
+// createPagesRequest is the request body for enabling a Pages site, the
+// domain and HTTPS settings can only be updated afterwards
+type createPagesRequest struct {
+	BuildType string       `json:"build_type,omitempty"`
+	Source    *PagesSource `json:"source,omitempty"`
+}
+
+func (in *client) GetPages(ctx context.Context, org, repoName string) (*Pages, error) {
+	req, err := in.c.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/pages", org, repoName), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pages Pages
+	resp, err := in.c.Do(ctx, req, &pages)
+	if err != nil {
+		// the site is not enabled
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &pages, nil
+}
+
+func (in *client) CreatePages(ctx context.Context, org, repoName string, pages *Pages) error {
+	body := &createPagesRequest{BuildType: pages.BuildType, Source: pages.Source}
+	return in.do(ctx, http.MethodPost, fmt.Sprintf("repos/%s/%s/pages", org, repoName), body, nil)
+}
+
+func (in *client) UpdatePages(ctx context.Context, org, repoName string, pages *Pages) error {
+	return in.do(ctx, http.MethodPut, fmt.Sprintf("repos/%s/%s/pages", org, repoName), pages, nil)
+}
+
+func (in *client) DeletePages(ctx context.Context, org, repoName string) error {
+	return in.doDelete(ctx, fmt.Sprintf("repos/%s/%s/pages", org, repoName))
+}
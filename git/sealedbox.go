@@ -0,0 +1,82 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// sealedBoxOverhead is the length of the ephemeral public key prepended to a sealed box
+const sealedBoxOverhead = 32
+
+// sealedBoxNonce derives the nonce of a sealed box the same way libsodium
+// does, blake2b(ephemeralPublicKey || recipientPublicKey)
+func sealedBoxNonce(ephemeralPublicKey, publicKey *[32]byte) (*[24]byte, error) {
+	h, err := blake2b.New(24, nil)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(ephemeralPublicKey[:])
+	h.Write(publicKey[:])
+
+	var nonce [24]byte
+	copy(nonce[:], h.Sum(nil))
+	return &nonce, nil
+}
+
+// sealBox encrypts message for publicKey as a libsodium crypto_box_seal sealed box
+func sealBox(message []byte, publicKey *[32]byte) ([]byte, error) {
+	ephemeralPublicKey, ephemeralPrivateKey, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := sealedBoxNonce(ephemeralPublicKey, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, sealedBoxOverhead+box.Overhead+len(message))
+	out = append(out, ephemeralPublicKey[:]...)
+	return box.Seal(out, message, nonce, publicKey, ephemeralPrivateKey), nil
+}
+
+// encryptSecret seals value with the base64 encoded public key GitHub hands
+// out for Actions secrets and returns the base64 encoded result
+func encryptSecret(publicKey string, value []byte) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode public key: %v", err)
+	}
+	if len(decoded) != 32 {
+		return "", fmt.Errorf("public key must be 32 bytes, got %d", len(decoded))
+	}
+
+	var key [32]byte
+	copy(key[:], decoded)
+
+	sealed, err := sealBox(value, &key)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
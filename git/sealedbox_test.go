@@ -0,0 +1,67 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"golang.org/x/crypto/nacl/box"
+)
+
+func TestEncryptSecret(t *testing.T) {
+	publicKey, privateKey, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := encryptSecret(base64.StdEncoding.EncodeToString(publicKey[:]), []byte("hunter2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// open the sealed box the way libsodium's crypto_box_seal_open does
+	var ephemeralPublicKey [32]byte
+	copy(ephemeralPublicKey[:], sealed[:sealedBoxOverhead])
+	nonce, err := sealedBoxNonce(&ephemeralPublicKey, publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opened, ok := box.Open(nil, sealed[sealedBoxOverhead:], nonce, &ephemeralPublicKey, privateKey)
+	if !ok {
+		t.Fatal("unable to open sealed box")
+	}
+	if string(opened) != "hunter2" {
+		t.Errorf("expected %q, got %q", "hunter2", opened)
+	}
+}
+
+func TestEncryptSecretInvalidKey(t *testing.T) {
+	if _, err := encryptSecret("not base64!", []byte("value")); err == nil {
+		t.Error("expected an error for a malformed key")
+	}
+	if _, err := encryptSecret(base64.StdEncoding.EncodeToString([]byte("short")), []byte("value")); err == nil {
+		t.Error("expected an error for a short key")
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+)
+
+// securityStatus is a single feature of the security_and_analysis repository field
+type securityStatus struct {
+	Status string `json:"status"`
+}
+
+// securityAndAnalysis holds the features go-github does not expose on the repository
+type securityAndAnalysis struct {
+	SecretScanning               *securityStatus `json:"secret_scanning,omitempty"`
+	SecretScanningPushProtection *securityStatus `json:"secret_scanning_push_protection,omitempty"`
+}
+
+type securityRepository struct {
+	SecurityAndAnalysis *securityAndAnalysis `json:"security_and_analysis,omitempty"`
+}
+
+// featureEnabled is the response body of the enablement endpoints
+type featureEnabled struct {
+	Enabled bool `json:"enabled"`
+}
+
+func (in *client) GetSecurity(ctx context.Context, org, repoName string) (*v1alpha1.RepositorySecurityStatus, error) {
+	status := &v1alpha1.RepositorySecurityStatus{}
+
+	var err error
+	if status.DependabotAlerts, err = in.featureEnabled(ctx, fmt.Sprintf("repos/%s/%s/vulnerability-alerts", org, repoName)); err != nil {
+		return nil, err
+	}
+	if status.DependabotSecurityUpdates, err = in.featureEnabled(ctx, fmt.Sprintf("repos/%s/%s/automated-security-fixes", org, repoName)); err != nil {
+		return nil, err
+	}
+	if status.PrivateVulnerabilityReporting, err = in.featureEnabled(ctx, fmt.Sprintf("repos/%s/%s/private-vulnerability-reporting", org, repoName)); err != nil {
+		return nil, err
+	}
+
+	var repo securityRepository
+	if err := in.do(ctx, http.MethodGet, fmt.Sprintf("repos/%s/%s", org, repoName), nil, &repo); err != nil {
+		return nil, err
+	}
+	if sa := repo.SecurityAndAnalysis; sa != nil {
+		status.SecretScanning = sa.SecretScanning != nil && sa.SecretScanning.Status == "enabled"
+		status.SecretScanningPushProtection = sa.SecretScanningPushProtection != nil && sa.SecretScanningPushProtection.Status == "enabled"
+	}
+	return status, nil
+}
+
+func (in *client) UpdateSecurity(ctx context.Context, org, repoName string, security *v1alpha1.RepositorySecurity) error {
+	alertsURL := fmt.Sprintf("repos/%s/%s/vulnerability-alerts", org, repoName)
+	fixesURL := fmt.Sprintf("repos/%s/%s/automated-security-fixes", org, repoName)
+
+	// security updates depend on alerts, enable alerts first and disable them last
+	if enabled := security.DependabotAlerts; enabled != nil && *enabled {
+		if err := in.setFeature(ctx, alertsURL, true); err != nil {
+			return err
+		}
+	}
+	if enabled := security.DependabotSecurityUpdates; enabled != nil {
+		if err := in.setFeature(ctx, fixesURL, *enabled); err != nil {
+			return err
+		}
+	}
+	if enabled := security.DependabotAlerts; enabled != nil && !*enabled {
+		if err := in.setFeature(ctx, alertsURL, false); err != nil {
+			return err
+		}
+	}
+
+	if enabled := security.PrivateVulnerabilityReporting; enabled != nil {
+		if err := in.setFeature(ctx, fmt.Sprintf("repos/%s/%s/private-vulnerability-reporting", org, repoName), *enabled); err != nil {
+			return err
+		}
+	}
+
+	sa := &securityAndAnalysis{
+		SecretScanning:               newSecurityStatus(security.SecretScanning),
+		SecretScanningPushProtection: newSecurityStatus(security.SecretScanningPushProtection),
+	}
+	if sa.SecretScanning == nil && sa.SecretScanningPushProtection == nil {
+		return nil
+	}
+	return in.do(ctx, http.MethodPatch, fmt.Sprintf("repos/%s/%s", org, repoName), &securityRepository{SecurityAndAnalysis: sa}, nil)
+}
+
+// featureEnabled reports whether the feature behind u is enabled, the
+// endpoints either answer with a body or with 204 or 404 only
+func (in *client) featureEnabled(ctx context.Context, u string) (bool, error) {
+	req, err := in.c.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return false, err
+	}
+
+	var feature featureEnabled
+	resp, err := in.c.Do(ctx, req, &feature)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	if resp.StatusCode == http.StatusNoContent {
+		return true, nil
+	}
+	return feature.Enabled, nil
+}
+
+// setFeature enables the feature behind u with PUT or disables it with DELETE
+func (in *client) setFeature(ctx context.Context, u string, enabled bool) error {
+	if enabled {
+		return in.do(ctx, http.MethodPut, u, nil, nil)
+	}
+	return in.do(ctx, http.MethodDelete, u, nil, nil)
+}
+
+func newSecurityStatus(enabled *bool) *securityStatus {
+	if enabled == nil {
+		return nil
+	}
+	if *enabled {
+		return &securityStatus{Status: "enabled"}
+	}
+	return &securityStatus{Status: "disabled"}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v28/github"
+	"go.hein.dev/github-controller/api/v1alpha1"
+)
+
+func newServerClient(t *testing.T, server *httptest.Server) *client {
+	c := github.NewClient(nil)
+	base, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.BaseURL = base
+	return &client{c: c}
+}
+
+func TestGetSecurity(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/org/repo/vulnerability-alerts", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/repos/org/repo/automated-security-fixes", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"enabled":false,"paused":false}`))
+	})
+	mux.HandleFunc("/repos/org/repo/private-vulnerability-reporting", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/repos/org/repo", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"security_and_analysis":{"secret_scanning":{"status":"enabled"},"secret_scanning_push_protection":{"status":"disabled"}}}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	status, err := newServerClient(t, server).GetSecurity(context.Background(), "org", "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &v1alpha1.RepositorySecurityStatus{DependabotAlerts: true, SecretScanning: true}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("expected %+v, got %+v", expected, status)
+	}
+}
+
+func TestUpdateSecurityOrdersDependabotRequests(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name     string
+		security *v1alpha1.RepositorySecurity
+		expected []string
+	}{
+		{
+			name:     "enable",
+			security: &v1alpha1.RepositorySecurity{DependabotAlerts: &enabled, DependabotSecurityUpdates: &enabled},
+			expected: []string{"PUT /repos/org/repo/vulnerability-alerts", "PUT /repos/org/repo/automated-security-fixes"},
+		},
+		{
+			name:     "disable",
+			security: &v1alpha1.RepositorySecurity{DependabotAlerts: &disabled, DependabotSecurityUpdates: &disabled},
+			expected: []string{"DELETE /repos/org/repo/automated-security-fixes", "DELETE /repos/org/repo/vulnerability-alerts"},
+		},
+		{
+			name:     "secret scanning",
+			security: &v1alpha1.RepositorySecurity{SecretScanning: &enabled},
+			expected: []string{"PATCH /repos/org/repo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			if err := newServerClient(t, server).UpdateSecurity(context.Background(), "org", "repo", tt.security); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(requests, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, requests)
+			}
+		})
+	}
+}
@@ -20,16 +20,26 @@ import (
 	"context"
 	"flag"
 	"os"
+	"strings"
 	"time"
 
 	githubv1alpha1 "go.hein.dev/github-controller/api/v1alpha1"
 	"go.hein.dev/github-controller/controllers"
 	"go.hein.dev/github-controller/git"
+	"go.hein.dev/github-controller/policy"
+	"go.hein.dev/github-controller/receiver"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -48,13 +58,42 @@ func init() {
 func main() {
 	var resyncTimeout = time.Minute * 30
 	var metricsAddr string
+	var probeAddr string
+	var readyCacheTTL time.Duration
+	var webhookReceiverAddr string
 	var enableLeaderElection bool
+	var leaderElectionID string
+	var namespaces string
+	var labelSelector string
 	var actualDelete bool
+	var dryRun bool
+	var releasePausedOnDelete bool
+	var importTimeout time.Duration
+	var allowInsecureImportSources bool
+	var enablePolicyWebhook bool
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+	flag.StringVar(&probeAddr, "health-probe-addr", ":8081", "The address the healthz and readyz endpoints bind to.")
+	flag.DurationVar(&readyCacheTTL, "ready-cache-ttl", 30*time.Second, "How long the result of the GitHub readiness check is cached.")
+	flag.StringVar(&webhookReceiverAddr, "github-webhook-addr", "",
+		"The address the GitHub webhook receiver binds to. Leave empty to disable; requires GITHUB_WEBHOOK_SECRET to be set.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "",
+		"The name of the leader election configmap, instances running side by side need different names.")
+	flag.StringVar(&namespaces, "namespaces", "",
+		"Comma separated namespaces the manager watches. Leave empty to watch every namespace.")
+	flag.StringVar(&labelSelector, "label-selector", "",
+		"Only reconcile the Repositories, Keys, ActionsSecrets and RepositoryFiles matching the label selector, e.g. team=platform. Leave empty to reconcile all of them.")
 	flag.BoolVar(&actualDelete, "actual-delete", false, "default: false; when true it will actually delete repos/keys when the object is deleted.")
+	flag.BoolVar(&releasePausedOnDelete, "release-paused-on-delete", false,
+		"default: false; when true deleted Repositories and Keys that are paused are released without changing GitHub instead of waiting to be resumed.")
+	flag.BoolVar(&dryRun, "dry-run", false, "default: false; when true changes are logged, recorded as events and stored as planned changes instead of being made on GitHub.")
 
+	flag.DurationVar(&importTimeout, "import-timeout", 30*time.Minute, "How long a repository import may run before it is cancelled and retried.")
+	flag.BoolVar(&allowInsecureImportSources, "allow-insecure-import-sources", false,
+		"default: false; when true repositories are also imported from http:// and file:// sources, which can reach the network and filesystem of the manager.")
+	flag.BoolVar(&enablePolicyWebhook, "enable-policy-webhook", false,
+		"Reject Repositories the OrganizationPolicies don't allow with a validating webhook; requires the webhook certificates.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(func(o *zap.Options) {
@@ -66,25 +105,95 @@ func main() {
 		setupLog.Error(err, "unable to setup github client")
 		os.Exit(1)
 	}
+	if dryRun {
+		setupLog.Info("dry-run enabled, GitHub will not be changed")
+		gitclient = git.DryRun(gitclient, ctrl.Log.WithName("dry-run"))
+	}
+
+	var selector labels.Selector
+	if labelSelector != "" {
+		if selector, err = labels.Parse(labelSelector); err != nil {
+			setupLog.Error(err, "unable to parse label selector")
+			os.Exit(1)
+		}
+	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:             scheme,
-		MetricsBindAddress: metricsAddr,
-		LeaderElection:     enableLeaderElection,
-		Port:               9443,
-		SyncPeriod:         &resyncTimeout,
-	})
+	options := ctrl.Options{
+		Scheme:                 scheme,
+		MetricsBindAddress:     metricsAddr,
+		HealthProbeBindAddress: probeAddr,
+		LeaderElection:         enableLeaderElection,
+		LeaderElectionID:       leaderElectionID,
+		Port:                   9443,
+		SyncPeriod:             &resyncTimeout,
+	}
+	var watchedNamespaces []string
+	for _, namespace := range strings.Split(namespaces, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			watchedNamespaces = append(watchedNamespaces, namespace)
+		}
+	}
+	if len(watchedNamespaces) > 0 {
+		setupLog.Info("watching namespaces", "namespaces", watchedNamespaces)
+		options.NewCache = cache.MultiNamespacedCacheBuilder(watchedNamespaces)
+		options.NewClient = controllers.NamespacedClient
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	repositoryRecorder := mgr.GetEventRecorderFor("repository-controller")
+	keyRecorder := mgr.GetEventRecorderFor("key-controller")
+	actionsSecretRecorder := mgr.GetEventRecorderFor("actionssecret-controller")
+	repositoryFileRecorder := mgr.GetEventRecorderFor("repositoryfile-controller")
+	customPropertyRecorder := mgr.GetEventRecorderFor("organizationcustomproperty-controller")
+	if dryRun {
+		repositoryRecorder = controllers.DryRunRecorder(repositoryRecorder)
+		keyRecorder = controllers.DryRunRecorder(keyRecorder)
+		actionsSecretRecorder = controllers.DryRunRecorder(actionsSecretRecorder)
+		repositoryFileRecorder = controllers.DryRunRecorder(repositoryFileRecorder)
+		customPropertyRecorder = controllers.DryRunRecorder(customPropertyRecorder)
+	}
+
+	var repositoryEvents, keyEvents chan event.GenericEvent
+	if webhookReceiverAddr != "" {
+		secret := os.Getenv("GITHUB_WEBHOOK_SECRET")
+		if secret == "" {
+			setupLog.Error(nil, "GITHUB_WEBHOOK_SECRET must be set to enable the github webhook receiver")
+			os.Exit(1)
+		}
+
+		repositoryEvents = make(chan event.GenericEvent, 100)
+		keyEvents = make(chan event.GenericEvent, 100)
+		if err = mgr.Add(&receiver.Receiver{
+			Client:           mgr.GetClient(),
+			Log:              ctrl.Log.WithName("receiver"),
+			Addr:             webhookReceiverAddr,
+			Secret:           []byte(secret),
+			RepositoryEvents: repositoryEvents,
+			KeyEvents:        keyEvents,
+		}); err != nil {
+			setupLog.Error(err, "unable to add github webhook receiver")
+			os.Exit(1)
+		}
+	}
+
 	if err = (&controllers.RepositoryReconciler{
-		Client:       mgr.GetClient(),
-		Log:          ctrl.Log.WithName("controllers").WithName("Repository"),
-		Scheme:       mgr.GetScheme(),
-		GitClient:    gitclient,
-		ActualDelete: actualDelete,
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName("Repository"),
+		Scheme:        mgr.GetScheme(),
+		GitClient:     gitclient,
+		ActualDelete:  actualDelete,
+		Recorder:      repositoryRecorder,
+		GitHubEvents:  repositoryEvents,
+		Selector:      selector,
+		ImportTimeout: importTimeout,
+
+		AllowInsecureImportSources: allowInsecureImportSources,
+		ReleasePausedOnDelete:      releasePausedOnDelete,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Repository")
 		os.Exit(1)
@@ -95,12 +204,102 @@ func main() {
 		Scheme:       mgr.GetScheme(),
 		GitClient:    gitclient,
 		ActualDelete: actualDelete,
+		Recorder:     keyRecorder,
+		GitHubEvents: keyEvents,
+		Selector:     selector,
+
+		ReleasePausedOnDelete: releasePausedOnDelete,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Key")
 		os.Exit(1)
 	}
+	if err = (&controllers.ActionsSecretReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("ActionsSecret"),
+		Scheme:       mgr.GetScheme(),
+		GitClient:    gitclient,
+		ActualDelete: actualDelete,
+		Recorder:     actionsSecretRecorder,
+		Selector:     selector,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ActionsSecret")
+		os.Exit(1)
+	}
+	if err = (&controllers.RepositoryFileReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("RepositoryFile"),
+		Scheme:       mgr.GetScheme(),
+		GitClient:    gitclient,
+		ActualDelete: actualDelete,
+		Recorder:     repositoryFileRecorder,
+		Selector:     selector,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RepositoryFile")
+		os.Exit(1)
+	}
+	// organization wide properties are left to the instance watching every namespace
+	// and every label
+	if len(watchedNamespaces) == 0 && selector == nil {
+		if err = (&controllers.OrganizationCustomPropertyReconciler{
+			Client:       mgr.GetClient(),
+			Log:          ctrl.Log.WithName("controllers").WithName("OrganizationCustomProperty"),
+			Scheme:       mgr.GetScheme(),
+			GitClient:    gitclient,
+			ActualDelete: actualDelete,
+			Recorder:     customPropertyRecorder,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OrganizationCustomProperty")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	if enablePolicyWebhook {
+		// the webhook validates objects of every namespace, not only the watched ones
+		var reader client.Reader = mgr.GetClient()
+		if len(watchedNamespaces) > 0 {
+			reader = mgr.GetAPIReader()
+		}
+		server := mgr.GetWebhookServer()
+		server.Register(policy.WebhookPath, &webhook.Admission{
+			Handler: &policy.RepositoryValidator{Client: reader},
+		})
+		server.Register(policy.ActionsSecretWebhookPath, &webhook.Admission{
+			Handler: &policy.ActionsSecretValidator{Client: reader},
+		})
+		server.Register(policy.RepositoryFileWebhookPath, &webhook.Admission{
+			Handler: &policy.RepositoryFileValidator{Client: reader},
+		})
+		server.Register(policy.OrganizationCustomPropertyWebhookPath, &webhook.Admission{
+			Handler: &policy.OrganizationCustomPropertyValidator{Client: reader},
+		})
+		server.Register(policy.KeyWebhookPath, &webhook.Admission{
+			Handler: &policy.KeyValidator{Client: reader},
+		})
+	}
+
+	if err = mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to add healthz check")
+		os.Exit(1)
+	}
+	// the policy webhook is only reachable while the pod is ready and fails closed,
+	// it doesn't need GitHub so an unreachable GitHub must not block every write
+	readyCheck := (&git.ReadyCheck{Client: gitclient, TTL: readyCacheTTL}).Check
+	readyName := "github"
+	if enablePolicyWebhook {
+		setupLog.Info("policy webhook enabled, readiness does not check GitHub")
+		readyCheck, readyName = healthz.Ping, "ping"
+	}
+	if err = mgr.AddReadyzCheck(readyName, readyCheck); err != nil {
+		setupLog.Error(err, "unable to add readyz check")
+		os.Exit(1)
+	}
+
+	if err = metrics.Registry.Register(&controllers.StatusCollector{Reader: mgr.GetClient(), Selector: selector}); err != nil {
+		setupLog.Error(err, "unable to register metrics")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
@@ -0,0 +1,171 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mirror contains the functions to copy git repositories between hosts
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// refspecs are the refs that are copied, GitHub rejects pushes to hidden refs
+// such as refs/pull/* so a plain push --mirror can't be used
+var refspecs = []string{"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*"}
+
+// scpLike matches the user@host:path form of SSH URLs
+var scpLike = regexp.MustCompile(`^[^/@:]+@[^/:]+:`)
+
+// Credentials authenticate against a git host over HTTP(S)
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// ValidateSource checks that a source is fetched over HTTPS. Plain http:// and
+// file:// sources, which could reach the network or the filesystem of the
+// controller, are only accepted with allowInsecure. SSH sources are rejected
+// as the credentials only authenticate HTTP(S) requests.
+func ValidateSource(rawurl string, allowInsecure bool) error {
+	if scpLike.MatchString(rawurl) {
+		return fmt.Errorf("git URL %q uses ssh, only https sources are supported", rawurl)
+	}
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return fmt.Errorf("invalid git URL %q: %v", rawurl, err)
+	}
+	switch u.Scheme {
+	case "https":
+	case "ssh":
+		return fmt.Errorf("git URL %q uses ssh, only https sources are supported", rawurl)
+	case "http", "file":
+		if !allowInsecure {
+			return fmt.Errorf("git URL %q must use https, %s sources are only allowed with --allow-insecure-import-sources", rawurl, u.Scheme)
+		}
+		if u.Scheme == "file" {
+			return nil
+		}
+	default:
+		return fmt.Errorf("git URL %q must use https", rawurl)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("git URL %q has no host", rawurl)
+	}
+	return nil
+}
+
+// Mirror copies every branch and tag of source to target, both are git URLs
+// and are authenticated with their credentials when they are set. The
+// credentials are handed to git in its environment, so they neither show up
+// in the arguments of the process nor in the config of the mirror.
+func Mirror(ctx context.Context, source string, sourceCreds *Credentials, target string, targetCreds *Credentials) error {
+	secrets := []*Credentials{sourceCreds, targetCreds}
+	var config []string
+	for _, remote := range []struct {
+		url   string
+		creds *Credentials
+	}{{source, sourceCreds}, {target, targetCreds}} {
+		header, err := credentialsConfig(remote.url, remote.creds)
+		if err != nil {
+			return fmt.Errorf("%s", redact(err.Error(), secrets))
+		}
+		config = append(config, header...)
+	}
+	env := configEnv(config)
+
+	dir, err := ioutil.TempDir("", "mirror")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := run(ctx, "", env, secrets, "clone", "--mirror", source, dir); err != nil {
+		return err
+	}
+	return run(ctx, dir, env, secrets, append([]string{"push", target}, refspecs...)...)
+}
+
+// credentialsConfig returns the git config key and value that authenticate the
+// HTTP(S) requests to the URL, the header is scoped to the URL so the
+// credentials of one remote are never sent to the other
+func credentialsConfig(rawurl string, creds *Credentials) ([]string, error) {
+	if creds == nil || scpLike.MatchString(rawurl) {
+		return nil, nil
+	}
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid git URL: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, nil
+	}
+	u.User = nil
+
+	auth := base64.StdEncoding.EncodeToString([]byte(creds.Username + ":" + creds.Password))
+	return []string{"http." + u.String() + ".extraHeader", "Authorization: Basic " + auth}, nil
+}
+
+// configEnv turns key and value pairs into the environment variables git reads
+// its config from
+func configEnv(config []string) []string {
+	env := []string{"GIT_CONFIG_COUNT=" + strconv.Itoa(len(config)/2)}
+	for i := 0; i+1 < len(config); i += 2 {
+		env = append(env,
+			fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", i/2, config[i]),
+			fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", i/2, config[i+1]),
+		)
+	}
+	return env
+}
+
+// run runs git in dir, the output is returned as error without the secrets
+func run(ctx context.Context, dir string, env []string, secrets []*Credentials, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s failed: %v: %s", args[0], err, redact(strings.TrimSpace(out.String()), secrets))
+	}
+	return nil
+}
+
+// redact removes the passwords from s, including their URL and header encoded forms
+func redact(s string, secrets []*Credentials) string {
+	for _, creds := range secrets {
+		if creds == nil || creds.Password == "" {
+			continue
+		}
+		s = strings.Replace(s, creds.Password, "REDACTED", -1)
+		s = strings.Replace(s, url.QueryEscape(creds.Password), "REDACTED", -1)
+		s = strings.Replace(s, url.PathEscape(creds.Password), "REDACTED", -1)
+		s = strings.Replace(s, base64.StdEncoding.EncodeToString([]byte(creds.Username+":"+creds.Password)), "REDACTED", -1)
+	}
+	return s
+}
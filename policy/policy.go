@@ -0,0 +1,206 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy decides which repositories and organizations a namespace may
+// manage based on the OrganizationPolicy objects, it is shared by the
+// admission webhook and the reconcilers
+package policy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+)
+
+// Violation explains why the policies don't allow a repository
+type Violation struct {
+	Reason string
+}
+
+func (v *Violation) Error() string {
+	return v.Reason
+}
+
+// Check evaluates the policies for the repository, the error is a *Violation
+// when the policies don't allow it. Only repositories of the namespace that
+// were created before the repository count against MaxRepositories.
+func Check(ctx context.Context, reader client.Reader, repository *v1alpha1.Repository) error {
+	var policies v1alpha1.OrganizationPolicyList
+	if err := reader.List(ctx, &policies); err != nil {
+		return err
+	}
+	if len(policies.Items) == 0 {
+		return nil
+	}
+
+	var repositories v1alpha1.RepositoryList
+	if err := reader.List(ctx, &repositories, client.InNamespace(repository.Namespace)); err != nil {
+		return err
+	}
+	existing := 0
+	for i := range repositories.Items {
+		other := &repositories.Items[i]
+		if other.Name != repository.Name && other.DeletionTimestamp.IsZero() &&
+			strings.EqualFold(other.Spec.Organization, repository.Spec.Organization) &&
+			createdBefore(other, repository) {
+			existing++
+		}
+	}
+
+	return Evaluate(policies.Items, repository, existing)
+}
+
+// createdBefore orders repositories by creation, a repository that is not
+// created yet comes last
+func createdBefore(a, b *v1alpha1.Repository) bool {
+	switch {
+	case b.CreationTimestamp.IsZero():
+		return true
+	case a.CreationTimestamp.Equal(&b.CreationTimestamp):
+		return a.Name < b.Name
+	default:
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+}
+
+// Evaluate returns a *Violation when the policies don't allow the repository,
+// existing is the number of other repositories the namespace of the repository
+// manages in its organization. Without any policy every repository is
+// allowed, otherwise one policy of the organization has to allow it.
+func Evaluate(policies []v1alpha1.OrganizationPolicy, repository *v1alpha1.Repository, existing int) error {
+	if len(policies) == 0 {
+		return nil
+	}
+
+	org := repository.Spec.Organization
+	var reasons []string
+	for i := range policies {
+		policy := &policies[i]
+		if !strings.EqualFold(policy.Spec.Organization, org) {
+			continue
+		}
+
+		reason := check(policy, repository, existing)
+		if reason == "" {
+			return nil
+		}
+		reasons = append(reasons, fmt.Sprintf("OrganizationPolicy %s: %s", policy.Name, reason))
+	}
+
+	if len(reasons) == 0 {
+		return &Violation{Reason: fmt.Sprintf("no OrganizationPolicy allows managing repositories in organization %q", org)}
+	}
+	sort.Strings(reasons)
+	return &Violation{Reason: fmt.Sprintf("repository %s/%s is not allowed, %s", org, repository.GitHubName(), strings.Join(reasons, "; "))}
+}
+
+// CheckOrganization evaluates the policies for an object of the namespace that
+// manages the organization itself, like organization secrets, the error is a
+// *Violation when the policies don't allow it. Cluster scoped objects pass an
+// empty namespace.
+func CheckOrganization(ctx context.Context, reader client.Reader, namespace, org string) error {
+	var policies v1alpha1.OrganizationPolicyList
+	if err := reader.List(ctx, &policies); err != nil {
+		return err
+	}
+	return EvaluateOrganization(policies.Items, namespace, org)
+}
+
+// EvaluateOrganization returns a *Violation when no policy of the organization
+// lists the namespace, an empty namespace only needs a policy of the
+// organization. Without any policy every organization is allowed.
+func EvaluateOrganization(policies []v1alpha1.OrganizationPolicy, namespace, org string) error {
+	if len(policies) == 0 {
+		return nil
+	}
+
+	for i := range policies {
+		policy := &policies[i]
+		if strings.EqualFold(policy.Spec.Organization, org) &&
+			(namespace == "" || containsString(policy.Spec.Namespaces, namespace)) {
+			return nil
+		}
+	}
+
+	if namespace == "" {
+		return &Violation{Reason: fmt.Sprintf("no OrganizationPolicy allows managing organization %q", org)}
+	}
+	return &Violation{Reason: fmt.Sprintf("no OrganizationPolicy allows namespace %q to manage organization %q", namespace, org)}
+}
+
+// CheckRepositoryRef evaluates the policies for the repository an object of
+// the namespace references, a repository that does not exist yet is checked
+// once it is created
+func CheckRepositoryRef(ctx context.Context, reader client.Reader, namespace, name string) error {
+	var repository v1alpha1.Repository
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &repository); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return Check(ctx, reader, &repository)
+}
+
+// Visibility returns the visibility the repository is created with
+func Visibility(repository *v1alpha1.Repository) v1alpha1.RepositoryVisibility {
+	if repository.Spec.Settings.Private {
+		return v1alpha1.PrivateRepository
+	}
+	return v1alpha1.PublicRepository
+}
+
+// check returns why the policy does not allow the repository, empty when it does
+func check(policy *v1alpha1.OrganizationPolicy, repository *v1alpha1.Repository, existing int) string {
+	if !containsString(policy.Spec.Namespaces, repository.Namespace) {
+		return fmt.Sprintf("namespace %q is not allowed", repository.Namespace)
+	}
+
+	if prefix := policy.Spec.NamePrefix; prefix != "" && !strings.HasPrefix(repository.GitHubName(), prefix) {
+		return fmt.Sprintf("name %q does not start with %q", repository.GitHubName(), prefix)
+	}
+
+	if visibility := Visibility(repository); len(policy.Spec.Visibilities) > 0 {
+		allowed := false
+		for _, v := range policy.Spec.Visibilities {
+			allowed = allowed || v == visibility
+		}
+		if !allowed {
+			return fmt.Sprintf("visibility %q is not allowed", visibility)
+		}
+	}
+
+	if max := policy.Spec.MaxRepositories; max != nil && int32(existing) >= *max {
+		return fmt.Sprintf("namespace %q already manages %d repositories", repository.Namespace, *max)
+	}
+
+	return ""
+}
+
+func containsString(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
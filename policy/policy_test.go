@@ -0,0 +1,118 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+)
+
+func TestEvaluate(t *testing.T) {
+	max := int32(2)
+	policies := []v1alpha1.OrganizationPolicy{{
+		ObjectMeta: metav1.ObjectMeta{Name: "orgname"},
+		Spec: v1alpha1.OrganizationPolicySpec{
+			Organization:    "orgname",
+			Namespaces:      []string{"team-a"},
+			NamePrefix:      "team-",
+			Visibilities:    []v1alpha1.RepositoryVisibility{v1alpha1.PrivateRepository},
+			MaxRepositories: &max,
+		},
+	}}
+
+	repository := func(namespace, org, name string, private bool) *v1alpha1.Repository {
+		return &v1alpha1.Repository{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: v1alpha1.RepositorySpec{
+				Organization: org,
+				Settings:     v1alpha1.RepositorySettings{Private: private},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		policies   []v1alpha1.OrganizationPolicy
+		repository *v1alpha1.Repository
+		existing   int
+		allowed    bool
+	}{
+		{"no policies", nil, repository("team-b", "other", "repo", false), 0, true},
+		{"allowed", policies, repository("team-a", "OrgName", "team-repo", true), 1, true},
+		{"organization without policy", policies, repository("team-a", "other", "team-repo", true), 0, false},
+		{"namespace", policies, repository("team-b", "orgname", "team-repo", true), 0, false},
+		{"name prefix", policies, repository("team-a", "orgname", "repo", true), 0, false},
+		{"visibility", policies, repository("team-a", "orgname", "team-repo", false), 0, false},
+		{"max repositories", policies, repository("team-a", "orgname", "team-repo", true), 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Evaluate(tt.policies, tt.repository, tt.existing)
+			if tt.allowed && err != nil {
+				t.Fatalf("expected the repository to be allowed, got %v", err)
+			}
+			if !tt.allowed {
+				if _, ok := err.(*Violation); !ok {
+					t.Fatalf("expected a violation, got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateOrganization(t *testing.T) {
+	policies := []v1alpha1.OrganizationPolicy{{
+		ObjectMeta: metav1.ObjectMeta{Name: "orgname"},
+		Spec: v1alpha1.OrganizationPolicySpec{
+			Organization: "orgname",
+			Namespaces:   []string{"team-a"},
+		},
+	}}
+
+	tests := []struct {
+		name      string
+		policies  []v1alpha1.OrganizationPolicy
+		namespace string
+		org       string
+		allowed   bool
+	}{
+		{"no policies", nil, "team-b", "other", true},
+		{"allowed", policies, "team-a", "OrgName", true},
+		{"namespace", policies, "team-b", "orgname", false},
+		{"organization without policy", policies, "team-a", "other", false},
+		{"cluster scoped", policies, "", "orgname", true},
+		{"cluster scoped without policy", policies, "", "other", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := EvaluateOrganization(tt.policies, tt.namespace, tt.org)
+			if tt.allowed && err != nil {
+				t.Fatalf("expected the organization to be allowed, got %v", err)
+			}
+			if !tt.allowed {
+				if _, ok := err.(*Violation); !ok {
+					t.Fatalf("expected a violation, got %v", err)
+				}
+			}
+		})
+	}
+}
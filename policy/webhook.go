@@ -0,0 +1,274 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"k8s.io/api/admission/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"go.hein.dev/github-controller/api/v1alpha1"
+)
+
+// the paths the validators are served on
+const (
+	WebhookPath                           = "/validate-github-go-hein-dev-v1alpha1-repository"
+	ActionsSecretWebhookPath              = "/validate-github-go-hein-dev-v1alpha1-actionssecret"
+	RepositoryFileWebhookPath             = "/validate-github-go-hein-dev-v1alpha1-repositoryfile"
+	OrganizationCustomPropertyWebhookPath = "/validate-github-go-hein-dev-v1alpha1-organizationcustomproperty"
+	KeyWebhookPath                        = "/validate-github-go-hein-dev-v1alpha1-key"
+)
+
+// +kubebuilder:webhook:path=/validate-github-go-hein-dev-v1alpha1-repository,mutating=false,failurePolicy=fail,sideEffects=None,groups=github.go.hein.dev,resources=repositories,verbs=create;update,versions=v1alpha1,name=vrepository.github.go.hein.dev,admissionReviewVersions=v1beta1
+
+// RepositoryValidator rejects Repository objects the OrganizationPolicies
+// don't allow
+type RepositoryValidator struct {
+	Client  client.Reader
+	decoder *admission.Decoder
+}
+
+// Handle implements admission.Handler
+func (v *RepositoryValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var repository v1alpha1.Repository
+	if err := v.decoder.Decode(req, &repository); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if repository.Namespace == "" {
+		repository.Namespace = req.Namespace
+	}
+
+	// objects being deleted and updates that don't touch what the policies
+	// check, like adding or removing finalizers, are always allowed
+	if !repository.DeletionTimestamp.IsZero() {
+		return admission.Allowed("")
+	}
+	if req.Operation == v1beta1.Update {
+		var old v1alpha1.Repository
+		if err := v.decoder.DecodeRaw(req.OldObject, &old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if strings.EqualFold(old.Spec.Organization, repository.Spec.Organization) &&
+			old.GitHubName() == repository.GitHubName() &&
+			Visibility(&old) == Visibility(&repository) {
+			return admission.Allowed("")
+		}
+	}
+
+	return response(Check(ctx, v.Client, &repository))
+}
+
+// InjectDecoder implements admission.DecoderInjector
+func (v *RepositoryValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-github-go-hein-dev-v1alpha1-actionssecret,mutating=false,failurePolicy=fail,sideEffects=None,groups=github.go.hein.dev,resources=actionssecrets,verbs=create;update,versions=v1alpha1,name=vactionssecret.github.go.hein.dev,admissionReviewVersions=v1beta1
+
+// ActionsSecretValidator rejects ActionsSecret objects uploading to a
+// repository or organization the OrganizationPolicies don't allow
+type ActionsSecretValidator struct {
+	Client  client.Reader
+	decoder *admission.Decoder
+}
+
+// Handle implements admission.Handler
+func (v *ActionsSecretValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var actionsSecret v1alpha1.ActionsSecret
+	if err := v.decoder.Decode(req, &actionsSecret); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if actionsSecret.Namespace == "" {
+		actionsSecret.Namespace = req.Namespace
+	}
+
+	if !actionsSecret.DeletionTimestamp.IsZero() {
+		return admission.Allowed("")
+	}
+	if req.Operation == v1beta1.Update {
+		var old v1alpha1.ActionsSecret
+		if err := v.decoder.DecodeRaw(req.OldObject, &old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if old.Spec.RepositoryRef == actionsSecret.Spec.RepositoryRef &&
+			strings.EqualFold(old.Spec.Organization, actionsSecret.Spec.Organization) {
+			return admission.Allowed("")
+		}
+	}
+
+	if actionsSecret.Spec.RepositoryRef != "" {
+		return response(CheckRepositoryRef(ctx, v.Client, actionsSecret.Namespace, actionsSecret.Spec.RepositoryRef))
+	}
+	if actionsSecret.Spec.Organization == "" {
+		return admission.Allowed("")
+	}
+	return response(CheckOrganization(ctx, v.Client, actionsSecret.Namespace, actionsSecret.Spec.Organization))
+}
+
+// InjectDecoder implements admission.DecoderInjector
+func (v *ActionsSecretValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-github-go-hein-dev-v1alpha1-repositoryfile,mutating=false,failurePolicy=fail,sideEffects=None,groups=github.go.hein.dev,resources=repositoryfiles,verbs=create;update,versions=v1alpha1,name=vrepositoryfile.github.go.hein.dev,admissionReviewVersions=v1beta1
+
+// RepositoryFileValidator rejects RepositoryFile objects committing to a
+// repository the OrganizationPolicies don't allow
+type RepositoryFileValidator struct {
+	Client  client.Reader
+	decoder *admission.Decoder
+}
+
+// Handle implements admission.Handler
+func (v *RepositoryFileValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var file v1alpha1.RepositoryFile
+	if err := v.decoder.Decode(req, &file); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if file.Namespace == "" {
+		file.Namespace = req.Namespace
+	}
+
+	if !file.DeletionTimestamp.IsZero() {
+		return admission.Allowed("")
+	}
+	if req.Operation == v1beta1.Update {
+		var old v1alpha1.RepositoryFile
+		if err := v.decoder.DecodeRaw(req.OldObject, &old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if old.Spec.RepositoryRef == file.Spec.RepositoryRef {
+			return admission.Allowed("")
+		}
+	}
+
+	return response(CheckRepositoryRef(ctx, v.Client, file.Namespace, file.Spec.RepositoryRef))
+}
+
+// InjectDecoder implements admission.DecoderInjector
+func (v *RepositoryFileValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-github-go-hein-dev-v1alpha1-organizationcustomproperty,mutating=false,failurePolicy=fail,sideEffects=None,groups=github.go.hein.dev,resources=organizationcustomproperties,verbs=create;update,versions=v1alpha1,name=vorganizationcustomproperty.github.go.hein.dev,admissionReviewVersions=v1beta1
+
+// OrganizationCustomPropertyValidator rejects OrganizationCustomProperty
+// objects of an organization no OrganizationPolicy covers
+type OrganizationCustomPropertyValidator struct {
+	Client  client.Reader
+	decoder *admission.Decoder
+}
+
+// Handle implements admission.Handler
+func (v *OrganizationCustomPropertyValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var property v1alpha1.OrganizationCustomProperty
+	if err := v.decoder.Decode(req, &property); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if !property.DeletionTimestamp.IsZero() {
+		return admission.Allowed("")
+	}
+	if req.Operation == v1beta1.Update {
+		var old v1alpha1.OrganizationCustomProperty
+		if err := v.decoder.DecodeRaw(req.OldObject, &old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if strings.EqualFold(old.Spec.Organization, property.Spec.Organization) {
+			return admission.Allowed("")
+		}
+	}
+
+	return response(CheckOrganization(ctx, v.Client, "", property.Spec.Organization))
+}
+
+// InjectDecoder implements admission.DecoderInjector
+func (v *OrganizationCustomPropertyValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-github-go-hein-dev-v1alpha1-key,mutating=false,failurePolicy=fail,sideEffects=None,groups=github.go.hein.dev,resources=keys,verbs=create;update,versions=v1alpha1,name=vkey.github.go.hein.dev,admissionReviewVersions=v1beta1
+
+// KeyValidator rejects Key objects adding a deploy key to, or exporting it
+// to, a repository the OrganizationPolicies don't allow
+type KeyValidator struct {
+	Client  client.Reader
+	decoder *admission.Decoder
+}
+
+// Handle implements admission.Handler
+func (v *KeyValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var key v1alpha1.Key
+	if err := v.decoder.Decode(req, &key); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if key.Namespace == "" {
+		key.Namespace = req.Namespace
+	}
+
+	if !key.DeletionTimestamp.IsZero() {
+		return admission.Allowed("")
+	}
+	if req.Operation == v1beta1.Update {
+		var old v1alpha1.Key
+		if err := v.decoder.DecodeRaw(req.OldObject, &old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if old.Spec.RepositoryRef == key.Spec.RepositoryRef && exportRef(&old) == exportRef(&key) {
+			return admission.Allowed("")
+		}
+	}
+
+	err := CheckRepositoryRef(ctx, v.Client, key.Namespace, key.Spec.RepositoryRef)
+	if err == nil && key.Spec.Export != nil {
+		err = CheckRepositoryRef(ctx, v.Client, key.Namespace, key.Spec.Export.RepositoryRef)
+	}
+	return response(err)
+}
+
+// InjectDecoder implements admission.DecoderInjector
+func (v *KeyValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// exportRef returns the repository the private key of the key is exported to
+func exportRef(key *v1alpha1.Key) string {
+	if key.Spec.Export == nil {
+		return ""
+	}
+	return key.Spec.Export.RepositoryRef
+}
+
+// response denies the request with the reason of a *Violation
+func response(err error) admission.Response {
+	if err == nil {
+		return admission.Allowed("")
+	}
+	if violation, ok := err.(*Violation); ok {
+		return admission.Denied(violation.Reason)
+	}
+	return admission.Errored(http.StatusInternalServerError, err)
+}
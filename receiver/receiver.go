@@ -0,0 +1,228 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package receiver contains the GitHub webhook receiver
+package receiver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-github/v28/github"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	v1alpha1 "go.hein.dev/github-controller/api/v1alpha1"
+)
+
+const (
+	// shutdownTimeout is how long in-flight deliveries get to finish on stop
+	shutdownTimeout = 5 * time.Second
+)
+
+// Receiver accepts GitHub webhook deliveries, verifies their signature and
+// enqueues the matching Repository and Key objects for an immediate reconcile
+type Receiver struct {
+	Client client.Client
+	Log    logr.Logger
+
+	// Addr is the address the receiver binds to
+	Addr string
+
+	// Secret is the webhook secret configured on the GitHub side
+	Secret []byte
+
+	// RepositoryEvents receives an event for every matching Repository
+	RepositoryEvents chan<- event.GenericEvent
+
+	// KeyEvents receives an event for every matching Key
+	KeyEvents chan<- event.GenericEvent
+}
+
+// payload holds the parts of a delivery the receiver needs, every event it
+// handles carries the repository it is about
+type payload struct {
+	Repository *github.Repository `json:"repository,omitempty"`
+}
+
+// Start runs the receiver until the stop channel is closed
+func (in *Receiver) Start(stop <-chan struct{}) error {
+	srv := &http.Server{Addr: in.Addr, Handler: in}
+
+	errCh := make(chan error, 1)
+	go func() {
+		in.Log.Info("starting github webhook receiver", "addr", in.Addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(ctx)
+	}
+}
+
+// ServeHTTP handles a single webhook delivery
+func (in *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := github.ValidatePayload(r, in.Secret)
+	if err != nil {
+		in.Log.Info("rejected webhook delivery", "reason", err.Error())
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	eventType := github.WebHookType(r)
+	log := in.Log.WithValues("event", eventType, "delivery", github.DeliveryID(r))
+
+	var repos, keys bool
+	switch eventType {
+	case "ping":
+		w.WriteHeader(http.StatusOK)
+		return
+	case "repository":
+		repos, keys = true, true
+	case "deploy_key":
+		keys = true
+	case "member", "team", "team_add":
+		repos = true
+	default:
+		log.Info("ignoring unsupported event")
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var p payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		log.Error(err, "unable to decode webhook payload")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if p.Repository == nil {
+		log.Info("ignoring event without repository")
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	org, name := p.Repository.GetOwner().GetLogin(), p.Repository.GetName()
+	log = log.WithValues("repository", org+"/"+name)
+
+	ctx := r.Context()
+	matched, err := in.matchRepositories(ctx, p.Repository.GetID(), org, name)
+	if err != nil {
+		log.Error(err, "unable to list repositories")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if repos {
+		for i := range matched {
+			in.enqueue(ctx, in.RepositoryEvents, &matched[i])
+		}
+	}
+
+	if keys {
+		matchedKeys, err := in.matchKeys(ctx, org, name, matched)
+		if err != nil {
+			log.Error(err, "unable to list keys")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		for i := range matchedKeys {
+			in.enqueue(ctx, in.KeyEvents, &matchedKeys[i])
+		}
+	}
+
+	log.Info("handled webhook delivery", "repositories", len(matched))
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// matchRepositories finds every Repository managing org/name or that was
+// synced with the repository id, the latter follows renames and transfers
+func (in *Receiver) matchRepositories(ctx context.Context, id int64, org, name string) ([]v1alpha1.Repository, error) {
+	var list v1alpha1.RepositoryList
+	if err := in.Client.List(ctx, &list); err != nil {
+		return nil, err
+	}
+
+	var matched []v1alpha1.Repository
+	for _, repository := range list.Items {
+		if (id != 0 && repository.Status.GitHubID == id) ||
+			(strings.EqualFold(repository.Spec.Organization, org) &&
+				strings.EqualFold(repository.GitHubName(), name)) {
+			matched = append(matched, repository)
+		}
+	}
+	return matched, nil
+}
+
+// matchKeys finds every Key that was synced to org/name or references one of
+// the matched repositories
+func (in *Receiver) matchKeys(ctx context.Context, org, name string, repositories []v1alpha1.Repository) ([]v1alpha1.Key, error) {
+	var list v1alpha1.KeyList
+	if err := in.Client.List(ctx, &list); err != nil {
+		return nil, err
+	}
+
+	var matched []v1alpha1.Key
+	for _, key := range list.Items {
+		if strings.EqualFold(key.Status.GitHubOrganization, org) &&
+			strings.EqualFold(key.Status.GitHubRepository, name) {
+			matched = append(matched, key)
+			continue
+		}
+		for _, repository := range repositories {
+			if key.Namespace == repository.Namespace && key.Spec.RepositoryRef == repository.Name {
+				matched = append(matched, key)
+				break
+			}
+		}
+	}
+	return matched, nil
+}
+
+// object is satisfied by every Kubernetes API type the receiver enqueues
+type object interface {
+	metav1.Object
+	runtime.Object
+}
+
+func (in *Receiver) enqueue(ctx context.Context, ch chan<- event.GenericEvent, obj object) {
+	if ch == nil {
+		return
+	}
+
+	select {
+	case ch <- event.GenericEvent{Meta: obj, Object: obj}:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2019 Christopher Hein <me@chrishein.com>.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package receiver
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1alpha1 "go.hein.dev/github-controller/api/v1alpha1"
+)
+
+var secret = []byte("s3cr3t")
+
+func newReceiver(t *testing.T, objs ...runtime.Object) (*Receiver, chan event.GenericEvent, chan event.GenericEvent) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	repositoryEvents := make(chan event.GenericEvent, 10)
+	keyEvents := make(chan event.GenericEvent, 10)
+	return &Receiver{
+		Client:           fake.NewFakeClientWithScheme(scheme, objs...),
+		Log:              logf.NullLogger{},
+		Secret:           secret,
+		RepositoryEvents: repositoryEvents,
+		KeyEvents:        keyEvents,
+	}, repositoryEvents, keyEvents
+}
+
+func delivery(eventType string, body []byte, key []byte) *http.Request {
+	mac := hmac.New(sha1.New, key)
+	mac.Write(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", eventType)
+	req.Header.Set("X-Hub-Signature", "sha1="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestReceiverRejectsInvalidSignature(t *testing.T) {
+	recv, repositoryEvents, _ := newReceiver(t)
+
+	rec := httptest.NewRecorder()
+	recv.ServeHTTP(rec, delivery("repository", []byte(`{}`), []byte("wrong")))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+	if len(repositoryEvents) != 0 {
+		t.Errorf("expected no events, got %d", len(repositoryEvents))
+	}
+}
+
+func TestReceiverEnqueuesMatchingObjects(t *testing.T) {
+	repository := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-repo", Namespace: "default"},
+		Spec:       v1alpha1.RepositorySpec{Organization: "awsctrl"},
+	}
+	other := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-repo", Namespace: "default"},
+		Spec:       v1alpha1.RepositorySpec{Organization: "awsctrl"},
+	}
+	key := &v1alpha1.Key{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-key", Namespace: "default"},
+		Spec:       v1alpha1.KeySpec{RepositoryRef: "test-repo"},
+	}
+	body := []byte(`{"action":"edited","repository":{"name":"test-repo","owner":{"login":"awsctrl"}}}`)
+
+	tests := []struct {
+		event        string
+		repositories int
+		keys         int
+	}{
+		{event: "repository", repositories: 1, keys: 1},
+		{event: "deploy_key", repositories: 0, keys: 1},
+		{event: "member", repositories: 1, keys: 0},
+		{event: "push", repositories: 0, keys: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			recv, repositoryEvents, keyEvents := newReceiver(t, repository, other, key)
+
+			rec := httptest.NewRecorder()
+			recv.ServeHTTP(rec, delivery(tt.event, body, secret))
+
+			if rec.Code != http.StatusAccepted {
+				t.Fatalf("expected status %d, got %d", http.StatusAccepted, rec.Code)
+			}
+			if len(repositoryEvents) != tt.repositories {
+				t.Errorf("expected %d repository events, got %d", tt.repositories, len(repositoryEvents))
+			}
+			if len(keyEvents) != tt.keys {
+				t.Errorf("expected %d key events, got %d", tt.keys, len(keyEvents))
+			}
+			if tt.repositories > 0 {
+				if evt := <-repositoryEvents; evt.Meta.GetName() != "test-repo" {
+					t.Errorf("expected test-repo to be enqueued, got %q", evt.Meta.GetName())
+				}
+			}
+		})
+	}
+}
+
+func TestReceiverMatchesRenamedRepositoriesByID(t *testing.T) {
+	repository := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-repo", Namespace: "default"},
+		Spec:       v1alpha1.RepositorySpec{Organization: "awsctrl"},
+		Status:     v1alpha1.RepositoryStatus{GitHubID: 42},
+	}
+	body := []byte(`{"action":"renamed","repository":{"id":42,"name":"renamed-repo","owner":{"login":"awsctrl"}}}`)
+
+	recv, repositoryEvents, _ := newReceiver(t, repository)
+
+	rec := httptest.NewRecorder()
+	recv.ServeHTTP(rec, delivery("repository", body, secret))
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, rec.Code)
+	}
+	if len(repositoryEvents) != 1 {
+		t.Errorf("expected 1 repository event, got %d", len(repositoryEvents))
+	}
+}